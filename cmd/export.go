@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"kick-chat/infra/postgres"
+	"kick-chat/internal/config"
+	"kick-chat/internal/initializer"
+	usecase "kick-chat/internal/usecases/chat"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const exportUsage = `usage: kick-chat export (-streamer <name> | -listener <id>) [flags]
+
+flags:
+  -streamer   streamer username
+  -listener   listener id, exports its streamer's messages
+  -from       start time, RFC3339 or YYYY-MM-DD (inclusive)
+  -to         end time, RFC3339 or YYYY-MM-DD (exclusive)
+  -sender     only messages from this sender
+  -format     csv, jsonl, html, vtt or srt (default csv)
+  -o          output file (default stdout)
+
+subtitle flags (vtt, srt):
+  -start          broadcast start, cue times are offsets from it (default -from)
+  -cue-duration   how long a message stays on screen (default 5s)
+  -max-lines      messages on screen at once (default 3)
+  -sender-format  color, plain or none (default color)`
+
+func runExport(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, exportUsage) }
+	streamer := flags.String("streamer", "", "")
+	listener := flags.String("listener", "", "")
+	from := flags.String("from", "", "")
+	to := flags.String("to", "", "")
+	sender := flags.String("sender", "", "")
+	format := flags.String("format", "csv", "")
+	output := flags.String("o", "", "")
+	start := flags.String("start", "", "")
+	cueDuration := flags.Duration("cue-duration", 0, "")
+	maxLines := flags.Int("max-lines", 0, "")
+	senderFormat := flags.String("sender-format", "", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	query := usecase.ExportQuery{
+		ListenerID: *listener,
+		Streamer:   *streamer,
+		Sender:     *sender,
+		Format:     *format,
+		Subtitles: usecase.SubtitleOptions{
+			CueDuration:  *cueDuration,
+			MaxLines:     *maxLines,
+			SenderFormat: *senderFormat,
+		},
+	}
+	var err error
+	if query.From, err = parseExportTime("from", *from); err != nil {
+		return err
+	}
+	if query.To, err = parseExportTime("to", *to); err != nil {
+		return err
+	}
+	if query.Subtitles.Start, err = parseExportTime("start", *start); err != nil {
+		return err
+	}
+
+	repo, err := postgres.OpenRepository(initializer.DatabaseURL(cfg))
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	export, err := usecase.NewMessageExportUseCase(repo).Prepare(query)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	started := time.Now()
+	count, err := export.WriteTo(ctx, w)
+	if err != nil {
+		return fmt.Errorf("%d mesajdan sonra durdu: %w", count, err)
+	}
+	fmt.Fprintf(os.Stderr, "%d mesaj %s içinde dışa aktarıldı\n", count, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+func parseExportTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("geçersiz -%s değeri: %s", name, value)
+}
@@ -5,6 +5,7 @@ import (
 	"kick-chat/internal/config"
 	usecase "kick-chat/internal/usecases/chat"
 	"log"
+	"os"
 
 	_ "kick-chat/logging"
 
@@ -16,6 +17,21 @@ func main() {
 	if err != nil {
 		log.Fatal("Config error:", err)
 	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatal("Migrate error: ", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(cfg, os.Args[2:]); err != nil {
+			log.Fatal("Export error: ", err)
+		}
+		return
+	}
+
 	usecase.ListenerManager = usecase.NewListenerManager()
 	app, err := bootstrap.NewApp(cfg)
 	if err != nil {
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AlertKindKeyword = "keyword"
+	AlertKindRegex   = "regex"
+	AlertKindLink    = "link"
+)
+
+// AlertRule fires when a message matches Pattern. Streamer nil means every channel.
+type AlertRule struct {
+	ID              uuid.UUID `json:"id"`
+	UserID          uuid.UUID `json:"user_id"`
+	Streamer        *string   `json:"streamer,omitempty"`
+	Kind            string    `json:"kind"`
+	Pattern         string    `json:"pattern"`
+	CooldownSeconds int       `json:"cooldown_seconds"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Alert is one message that matched a rule
+type Alert struct {
+	ID             uuid.UUID `json:"id"`
+	RuleID         uuid.UUID `json:"rule_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Streamer       string    `json:"streamer"`
+	KickMessageID  string    `json:"kick_message_id"`
+	SenderUsername string    `json:"sender_username"`
+	Content        string    `json:"content"`
+	Matched        string    `json:"matched"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AlertFilter pages through a user's alerts, newest first
+type AlertFilter struct {
+	UserID   uuid.UUID
+	RuleID   *uuid.UUID
+	Streamer string
+	Before   *MessageCursor
+	Limit    int
+}
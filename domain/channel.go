@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// ChannelInfo is the Kick metadata needed to start listening to a streamer
+type ChannelInfo struct {
+	Username   string    `json:"username"`
+	ChatroomID int       `json:"chatroom_id"`
+	KickUserID int       `json:"kick_user_id"`
+	ProfilePic string    `json:"profile_pic"`
+	Source     string    `json:"source"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
@@ -4,4 +4,13 @@ import "errors"
 
 var (
 	ErrNotFoundAuthorization = errors.New("authorization not found ")
+	// ErrForbidden is returned when an authenticated user touches data that isn't theirs
+	ErrForbidden = errors.New("forbidden")
+	ErrNotFound  = errors.New("not found")
+	// ErrInvalidInput wraps request validation failures so handlers can answer 400
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrQuotaExceeded is returned when a request would exceed the user's listening quota
+	ErrQuotaExceeded = errors.New("listen quota exceeded")
+	// ErrTransient marks storage errors that are worth retrying (connection loss, deadlocks...)
+	ErrTransient = errors.New("transient storage error")
 )
@@ -15,3 +15,49 @@ type ActiveListenerData struct {
 	EndTime          *time.Time
 	Duration         int
 }
+
+// ListenRequest is one user's open request to listen to a streamer
+type ListenRequest struct {
+	ID          uuid.UUID `json:"id"`
+	ListenerID  uuid.UUID `json:"listener_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Streamer    string    `json:"streamer"`
+	RequestTime time.Time `json:"request_time"`
+	EndTime     time.Time `json:"end_time"`
+	// Sinks names the outputs this request wants, nil means every configured sink
+	Sinks []string `json:"sinks,omitempty"`
+}
+
+// UserListener is a user's open request joined with its streamer and message volume
+type UserListener struct {
+	RequestID    uuid.UUID `json:"request_id"`
+	ListenerID   uuid.UUID `json:"listener_id"`
+	Streamer     string    `json:"streamer"`
+	ProfilePic   string    `json:"profile_pic,omitempty"`
+	RequestTime  time.Time `json:"request_time"`
+	EndTime      time.Time `json:"end_time"`
+	IsActive     bool      `json:"is_active"`
+	MessageCount int64     `json:"message_count"`
+	// State is the last persisted listener state, see usecase.ListenerState
+	State          string     `json:"state"`
+	StateChangedAt *time.Time `json:"state_changed_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+// ConnectionAttempt is one reconnect of a listener or of the socket it shares
+type ConnectionAttempt struct {
+	ListenerID  uuid.UUID
+	Scope       string
+	Attempt     int
+	Delay       time.Duration
+	Reason      string
+	AttemptedAt time.Time
+}
+
+// DesiredListener is an open request together with the persisted state of its row,
+// the reconciler's view of what should be running
+type DesiredListener struct {
+	ListenRequest
+	State          string
+	StateChangedAt *time.Time
+}
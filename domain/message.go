@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatMessage is a chat message as it is written to the messages table
+type ChatMessage struct {
+	ListenerID     uuid.UUID
+	KickMessageID  string
+	SenderID       int
+	SenderUsername string
+	SenderColor    string
+	Content        string
+	Timestamp      time.Time
+	HasLink        bool
+	ExtractedLinks []string
+}
+
+// StoredMessage is a row of the messages table joined with its streamer
+type StoredMessage struct {
+	ID             uuid.UUID `json:"id"`
+	ListenerID     uuid.UUID `json:"listener_id"`
+	Streamer       string    `json:"streamer"`
+	KickMessageID  string    `json:"kick_message_id,omitempty"`
+	SenderID       int       `json:"sender_id,omitempty"`
+	SenderUsername string    `json:"sender_username"`
+	SenderColor    string    `json:"sender_color,omitempty"`
+	Content        string    `json:"content"`
+	Timestamp      time.Time `json:"timestamp"`
+	HasLink        bool      `json:"has_link"`
+	ExtractedLinks []string  `json:"extracted_links,omitempty"`
+}
+
+// MessageCursor is the keyset position of a message: rows are ordered by (Timestamp, ID)
+type MessageCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// MessageFilter narrows a message history query. Nil/empty fields are ignored.
+// Only messages inside UserID's request windows on the streamer are returned.
+type MessageFilter struct {
+	ListenerID uuid.UUID
+	UserID     uuid.UUID
+	Sender     string
+	From       *time.Time
+	To         *time.Time
+	HasLink    *bool
+	Contains   string
+	After      *MessageCursor
+	Ascending  bool
+	Limit      int
+}
+
+// ExportFilter selects the messages of one streamer, either named directly or
+// through one of its listeners, oldest first. A UserID limits them to that user's
+// request windows; CLI exports leave it nil and read everything.
+type ExportFilter struct {
+	ListenerID *uuid.UUID
+	UserID     *uuid.UUID
+	Streamer   string
+	Sender     string
+	From       *time.Time
+	To         *time.Time
+}
+
+// SearchFilter is a full-text query over the messages the user's requests covered
+type SearchFilter struct {
+	UserID    uuid.UUID
+	Query     string
+	Streamers []string
+	Sender    string
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+	FacetSize int
+}
+
+type SearchHit struct {
+	StoredMessage
+	Rank float64 `json:"rank"`
+	// Snippet is HTML escaped with matches wrapped in <mark>
+	Snippet string `json:"snippet"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type SearchFacets struct {
+	Channels []FacetCount `json:"channels"`
+	Senders  []FacetCount `json:"senders"`
+	Days     []FacetCount `json:"days"`
+}
+
+type SearchResult struct {
+	Total  int          `json:"total"`
+	Hits   []SearchHit  `json:"hits"`
+	Facets SearchFacets `json:"facets"`
+}
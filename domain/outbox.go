@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// OutboxEvent is a bus message waiting in Postgres until the broker takes it
+type OutboxEvent struct {
+	ID         int64
+	RoutingKey string
+	Payload    []byte
+	CreatedAt  time.Time
+	Attempts   int
+}
+
+// RejectedEventsError is returned when the broker refused some events of a batch;
+// Indexes point into the batch, every other event was confirmed
+type RejectedEventsError struct {
+	Indexes []int
+}
+
+func (e *RejectedEventsError) Error() string {
+	return fmt.Sprintf("broker %d olayı reddetti", len(e.Indexes))
+}
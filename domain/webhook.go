@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusDead      = "dead"
+)
+
+const (
+	WebhookFormatJSON    = "json"
+	WebhookFormatDiscord = "discord"
+	WebhookFormatSlack   = "slack"
+)
+
+// WebhookEndpoint is a user's URL for chat, link or alert events. Secret signs
+// every delivery and is only shown when the endpoint is created.
+type WebhookEndpoint struct {
+	ID              uuid.UUID `json:"id"`
+	UserID          uuid.UUID `json:"user_id"`
+	URL             string    `json:"url"`
+	Format          string    `json:"format"`
+	Secret          string    `json:"secret,omitempty"`
+	Events          []string  `json:"events"`
+	Streamer        *string   `json:"streamer,omitempty"`
+	RateLimitPerMin int       `json:"rate_limit_per_minute"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one queued POST of an event to an endpoint
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id"`
+	EndpointID     uuid.UUID  `json:"endpoint_id"`
+	EventID        uuid.UUID  `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        []byte     `json:"-"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+
+	// Filled when claimed for sending
+	URL             string `json:"-"`
+	Format          string `json:"-"`
+	Secret          string `json:"-"`
+	RateLimitPerMin int    `json:"-"`
+}
+
+// WebhookDeliveryFilter pages through an endpoint's delivery log, newest first
+type WebhookDeliveryFilter struct {
+	EndpointID uuid.UUID
+	Status     string
+	Before     *MessageCursor
+	Limit      int
+}
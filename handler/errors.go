@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"errors"
+	"kick-chat/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatusFor maps domain errors to HTTP status codes, anything unknown is a 500
+func StatusFor(err error) int {
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &fiberErr):
+		return fiberErr.Code
+	case errors.Is(err, domain.ErrNotFoundAuthorization):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidInput):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return fiber.StatusUnprocessableEntity
+	}
+	return fiber.StatusInternalServerError
+}
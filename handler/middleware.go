@@ -18,8 +18,7 @@ func HandleBasic[R Request, Res Response](handler BasicHandler[R, Res]) fiber.Ha
 		res, err := handler.Handle(ctx, &req)
 
 		if err != nil {
-
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			return c.Status(StatusFor(err)).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		return c.JSON(res)
@@ -38,7 +37,7 @@ func HandleWithFiber[R Request, Res Response](handler FiberHandler[R, Res]) fibe
 		res, err := handler.Handle(c, ctx, &req)
 
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			return c.Status(StatusFor(err)).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		return c.JSON(res)
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// streamWriteTimeout bounds each write so a stalled client releases the stream,
+// the server WriteTimeout would otherwise cut long downloads
+const streamWriteTimeout = 30 * time.Second
+
+// Stream is a response body produced after the handler returns, for downloads
+// too large to hold in memory. Write runs once the status and headers are sent.
+type Stream struct {
+	ContentType string
+	FileName    string
+	Write       func(ctx context.Context, w io.Writer) error
+}
+
+type StreamHandler[R Request] interface {
+	Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *R) (*Stream, error)
+}
+
+// HandleStream maps errors like HandleWithFiber up to the point the stream starts;
+// later errors can only end the body early and are logged
+func HandleStream[R Request](handler StreamHandler[R]) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req R
+
+		if err := parseRequest(c, &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		ctx := c.UserContext()
+		stream, err := handler.Handle(c, ctx, &req)
+
+		if err != nil {
+			return c.Status(StatusFor(err)).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		c.Set(fiber.HeaderContentType, stream.ContentType)
+		if stream.FileName != "" {
+			c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", stream.FileName))
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+
+		conn := c.Context().Conn()
+		path := strings.Clone(c.Path())
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			// fiber's context is recycled once the handler returns, the stream gets its own
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if err := stream.Write(ctx, &deadlineWriter{conn: conn, w: w}); err != nil {
+				log.Printf("%s akışı yarıda kaldı: %v", path, err)
+			}
+			w.Flush()
+		})
+		return nil
+	}
+}
+
+type deadlineWriter struct {
+	conn net.Conn
+	w    io.Writer
+}
+
+func (d *deadlineWriter) Write(p []byte) (int, error) {
+	d.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return d.w.Write(p)
+}
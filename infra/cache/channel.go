@@ -0,0 +1,57 @@
+// Package cache holds Redis backed caches shared by every replica
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kick-chat/domain"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const channelKeyPrefix = "kick:channel:"
+
+// ChannelCache caches username -> channel metadata in Redis
+type ChannelCache struct {
+	client *redis.Client
+}
+
+func NewChannelCache(client *redis.Client) *ChannelCache {
+	return &ChannelCache{client: client}
+}
+
+func channelKey(username string) string {
+	return channelKeyPrefix + strings.ToLower(username)
+}
+
+// Get returns domain.ErrNotFound on a cache miss
+func (c *ChannelCache) Get(ctx context.Context, username string) (*domain.ChannelInfo, error) {
+	raw, err := c.client.Get(ctx, channelKey(username)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var info domain.ChannelInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *ChannelCache) Set(ctx context.Context, info domain.ChannelInfo, ttl time.Duration) error {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, channelKey(info.Username), raw, ttl).Err()
+}
+
+func (c *ChannelCache) Delete(ctx context.Context, username string) error {
+	return c.client.Del(ctx, channelKey(username)).Err()
+}
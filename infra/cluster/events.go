@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	eventChannelPrefix = "kick:events:"
+	eventStreamPrefix  = "kick:events:log:"
+)
+
+// RedisEventBus publishes live events on one pub/sub channel per streamer and
+// keeps the last replaySize of them in a capped stream for late joiners.
+type RedisEventBus struct {
+	client     *redis.Client
+	replaySize int64
+	replayTTL  time.Duration
+}
+
+func NewRedisEventBus(client *redis.Client, replaySize int, replayTTL time.Duration) *RedisEventBus {
+	return &RedisEventBus{
+		client:     client,
+		replaySize: int64(replaySize),
+		replayTTL:  replayTTL,
+	}
+}
+
+func (b *RedisEventBus) Publish(ctx context.Context, streamer string, payload []byte) error {
+	pipe := b.client.Pipeline()
+	pipe.Publish(ctx, eventChannelPrefix+streamer, payload)
+	if b.replaySize > 0 {
+		stream := eventStreamPrefix + streamer
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			MaxLen: b.replaySize,
+			Approx: true,
+			Values: map[string]interface{}{"e": payload},
+		})
+		// Streamers nobody listens to anymore drop out on their own
+		pipe.Expire(ctx, stream, b.replayTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Subscribe calls handle for every event published by any instance until ctx is done
+func (b *RedisEventBus) Subscribe(ctx context.Context, handle func(streamer string, payload []byte)) error {
+	sub := b.client.PSubscribe(ctx, eventChannelPrefix+"*")
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			streamer := strings.TrimPrefix(msg.Channel, eventChannelPrefix)
+			handle(streamer, []byte(msg.Payload))
+		}
+	}
+}
+
+// Replay returns up to limit of the streamer's latest events, oldest first
+func (b *RedisEventBus) Replay(ctx context.Context, streamer string, limit int) ([][]byte, error) {
+	entries, err := b.client.XRevRangeN(ctx, eventStreamPrefix+streamer, "+", "-", int64(limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([][]byte, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		if payload, ok := entries[i].Values["e"].(string); ok {
+			payloads = append(payloads, []byte(payload))
+		}
+	}
+	return payloads, nil
+}
@@ -0,0 +1,146 @@
+// Package cluster coordinates listener ownership between replicas through Redis
+package cluster
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	membersKey       = "kick:listener:instances"
+	leaseKeyPrefix   = "kick:listener:lease:"
+	reconcileChannel = "kick:listener:reconcile"
+)
+
+// renewScript extends a lease only while we still hold it, returns 0 when it was lost
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisCoordinator keeps a heartbeat per instance in a sorted set (score = expiry)
+// and one lease key per streamer holding the owning instance ID.
+type RedisCoordinator struct {
+	client      *redis.Client
+	instanceID  string
+	leaseTTL    time.Duration
+	instanceTTL time.Duration
+}
+
+func NewRedisCoordinator(client *redis.Client, instanceID string, leaseTTL, instanceTTL time.Duration) *RedisCoordinator {
+	return &RedisCoordinator{
+		client:      client,
+		instanceID:  instanceID,
+		leaseTTL:    leaseTTL,
+		instanceTTL: instanceTTL,
+	}
+}
+
+func leaseKey(streamer string) string {
+	return leaseKeyPrefix + streamer
+}
+
+func (c *RedisCoordinator) InstanceID() string {
+	return c.instanceID
+}
+
+func (c *RedisCoordinator) Heartbeat(ctx context.Context) error {
+	expiry := time.Now().Add(c.instanceTTL).UnixMilli()
+	return c.client.ZAdd(ctx, membersKey, redis.Z{Score: float64(expiry), Member: c.instanceID}).Err()
+}
+
+// Members returns live instances, pruning those whose heartbeat expired
+func (c *RedisCoordinator) Members(ctx context.Context) ([]string, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := c.client.ZRemRangeByScore(ctx, membersKey, "-inf", "("+now).Err(); err != nil {
+		return nil, err
+	}
+	return c.client.ZRange(ctx, membersKey, 0, -1).Result()
+}
+
+// Acquire takes the streamer's lease, or refreshes it when we already hold it
+func (c *RedisCoordinator) Acquire(ctx context.Context, streamer string) (bool, error) {
+	ok, err := c.client.SetNX(ctx, leaseKey(streamer), c.instanceID, c.leaseTTL).Result()
+	if err != nil || ok {
+		return ok, err
+	}
+	renewed, err := renewScript.Run(ctx, c.client, []string{leaseKey(streamer)}, c.instanceID, c.leaseTTL.Milliseconds()).Int()
+	return renewed == 1, err
+}
+
+// Renew extends every given lease and reports the ones another instance now holds
+func (c *RedisCoordinator) Renew(ctx context.Context, streamers []string) ([]string, error) {
+	if len(streamers) == 0 {
+		return nil, nil
+	}
+
+	// Run's NOSCRIPT fallback can't work inside a pipeline, which only queues the
+	// EVALSHA; the script is short enough to send in full every time
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.Cmd, len(streamers))
+	for i, streamer := range streamers {
+		cmds[i] = renewScript.Eval(ctx, pipe, []string{leaseKey(streamer)}, c.instanceID, c.leaseTTL.Milliseconds())
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	var lost []string
+	for i, cmd := range cmds {
+		if renewed, err := cmd.Int(); err == nil && renewed == 0 {
+			lost = append(lost, streamers[i])
+		}
+	}
+	return lost, nil
+}
+
+func (c *RedisCoordinator) Release(ctx context.Context, streamer string) error {
+	return releaseScript.Run(ctx, c.client, []string{leaseKey(streamer)}, c.instanceID).Err()
+}
+
+// Leave removes this instance from the member set so the others rebalance right away
+func (c *RedisCoordinator) Leave(ctx context.Context) error {
+	return c.client.ZRem(ctx, membersKey, c.instanceID).Err()
+}
+
+// Notify asks every instance to reconcile now instead of waiting for the next tick
+func (c *RedisCoordinator) Notify(ctx context.Context) error {
+	return c.client.Publish(ctx, reconcileChannel, c.instanceID).Err()
+}
+
+// Nudges delivers reconcile requests published by Notify until ctx is done
+func (c *RedisCoordinator) Nudges(ctx context.Context) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	sub := c.client.Subscribe(ctx, reconcileChannel)
+	go func() {
+		defer sub.Close()
+		defer close(out)
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const alertRuleColumns = `id, user_id, streamer, kind, pattern, cooldown_seconds, enabled, created_at`
+
+func scanAlertRule(row rowScanner) (domain.AlertRule, error) {
+	var rule domain.AlertRule
+	var streamer sql.NullString
+	err := row.Scan(&rule.ID, &rule.UserID, &streamer, &rule.Kind, &rule.Pattern,
+		&rule.CooldownSeconds, &rule.Enabled, &rule.CreatedAt)
+	if streamer.Valid {
+		rule.Streamer = &streamer.String
+	}
+	return rule, err
+}
+
+func (r *Repository) CreateAlertRule(ctx context.Context, rule domain.AlertRule) (*domain.AlertRule, error) {
+	query := `
+		INSERT INTO alert_rules (user_id, streamer, kind, pattern, cooldown_seconds, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + alertRuleColumns + `;`
+
+	created, err := scanAlertRule(r.db.QueryRowContext(ctx, query, rule.UserID, rule.Streamer, rule.Kind,
+		rule.Pattern, rule.CooldownSeconds, rule.Enabled))
+	if err != nil {
+		return nil, fmt.Errorf("alarm kuralı oluşturulurken hata: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *Repository) CountAlertRules(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM alert_rules WHERE user_id = $1;`, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("alarm kuralları sayılırken hata: %w", err)
+	}
+	return count, nil
+}
+
+func (r *Repository) GetUserAlertRules(ctx context.Context, userID uuid.UUID) ([]domain.AlertRule, error) {
+	return r.queryAlertRules(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE user_id = $1 ORDER BY created_at;`, userID)
+}
+
+// GetEnabledAlertRules loads every rule the matcher should evaluate
+func (r *Repository) GetEnabledAlertRules(ctx context.Context) ([]domain.AlertRule, error) {
+	return r.queryAlertRules(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE enabled = true;`)
+}
+
+func (r *Repository) queryAlertRules(ctx context.Context, query string, args ...any) ([]domain.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("alarm kuralları getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []domain.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("alarm kuralı okunurken hata: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *Repository) SetAlertRuleEnabled(ctx context.Context, userID, ruleID uuid.UUID, enabled bool) (*domain.AlertRule, error) {
+	query := `
+		UPDATE alert_rules SET enabled = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + alertRuleColumns + `;`
+
+	rule, err := scanAlertRule(r.db.QueryRowContext(ctx, query, ruleID, userID, enabled))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("alarm kuralı güncellenirken hata: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *Repository) DeleteAlertRule(ctx context.Context, userID, ruleID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1 AND user_id = $2;`, ruleID, userID)
+	if err != nil {
+		return fmt.Errorf("alarm kuralı silinirken hata: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// InsertAlert stores a match; the rule may have been deleted meanwhile, that is not an error
+func (r *Repository) InsertAlert(ctx context.Context, alert domain.Alert) (*domain.Alert, error) {
+	query := `
+		INSERT INTO alerts (rule_id, user_id, streamer, kick_message_id, sender_username, content, matched)
+		SELECT $1, $2, $3, NULLIF($4, ''), $5, $6, $7
+		WHERE EXISTS (SELECT 1 FROM alert_rules WHERE id = $1)
+		RETURNING id, created_at;`
+
+	err := r.db.QueryRowContext(ctx, query, alert.RuleID, alert.UserID, alert.Streamer, alert.KickMessageID,
+		alert.SenderUsername, alert.Content, alert.Matched).Scan(&alert.ID, &alert.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("alarm kaydedilirken hata: %w", err)
+	}
+	return &alert, nil
+}
+
+// GetAlerts pages through a user's alerts newest first, keyset on (created_at, id)
+func (r *Repository) GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.Alert, error) {
+	conditions := []string{"user_id = $1"}
+	args := []any{filter.UserID}
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.RuleID != nil {
+		add("rule_id = $%d", *filter.RuleID)
+	}
+	// Alerts keep the streamer as its listener was started, in any case
+	if filter.Streamer != "" {
+		add("LOWER(streamer) = LOWER($%d)", filter.Streamer)
+	}
+	if filter.Before != nil {
+		args = append(args, filter.Before.Timestamp, filter.Before.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	query := `
+		SELECT id, rule_id, user_id, streamer, COALESCE(kick_message_id, ''), sender_username, content, matched, created_at
+		FROM alerts
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $` + fmt.Sprint(len(args)) + `;`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("alarmlar getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []domain.Alert
+	for rows.Next() {
+		var a domain.Alert
+		if err := rows.Scan(&a.ID, &a.RuleID, &a.UserID, &a.Streamer, &a.KickMessageID, &a.SenderUsername,
+			&a.Content, &a.Matched, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("alarm satırı okunurken hata: %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
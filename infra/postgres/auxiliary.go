@@ -1,8 +1,11 @@
 package postgres
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"log"
+	"net"
 	"time"
 
 	"github.com/lib/pq"
@@ -24,6 +27,26 @@ func (r *Repository) isDuplicateKeyError(err error) bool {
 	return false
 }
 
+// isTransientError reports errors where retrying the same statement can succeed
+func (r *Repository) isTransientError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", // connection_exception
+			"40", // transaction_rollback (serialization_failure, deadlock_detected)
+			"53", // insufficient_resources
+			"57": // operator_intervention (admin_shutdown, cannot_connect_now)
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 func (r *Repository) startCleanupJob(interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	for range ticker.C {
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// OpenRepository connects without migrating or starting background jobs, used by CLI commands
+func OpenRepository(connString string) (*Repository, error) {
+	db, err := OpenDB(connString)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{db: db}, nil
+}
+
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+// CanReadStreamer is CanReadListener by streamer name: any listener or request of
+// the user on the streamer grants access to it. Names are stored as they were
+// first typed, so they are compared case-insensitively.
+func (r *Repository) CanReadStreamer(ctx context.Context, userID uuid.UUID, streamer string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM listeners own
+			JOIN streamers s ON s.id = own.streamer_id
+			LEFT JOIN user_listener_requests r ON r.listener_id = own.id AND r.user_id = $1
+			WHERE LOWER(s.username) = LOWER($2) AND (own.user_id = $1 OR r.id IS NOT NULL)
+		), EXISTS (SELECT 1 FROM streamers WHERE LOWER(username) = LOWER($2));`
+
+	var allowed, exists bool
+	if err := r.db.QueryRowContext(ctx, query, userID, streamer).Scan(&allowed, &exists); err != nil {
+		return false, fmt.Errorf("yayıncı yetkisi kontrol edilirken hata: %w", err)
+	}
+	if !exists {
+		return false, domain.ErrNotFound
+	}
+	return allowed, nil
+}
+
+// StreamMessages runs a single query over the whole range and hands rows to fn as
+// they come off the connection, so memory use does not grow with the export size.
+// An error from fn stops the query.
+func (r *Repository) StreamMessages(ctx context.Context, filter domain.ExportFilter, fn func(domain.StoredMessage) error) error {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where []string
+	if filter.ListenerID != nil {
+		where = append(where, `l.streamer_id = (SELECT streamer_id FROM listeners WHERE id = `+arg(*filter.ListenerID)+`)`)
+	} else {
+		where = append(where, `LOWER(s.username) = LOWER(`+arg(filter.Streamer)+`)`)
+	}
+	if filter.UserID != nil {
+		where = append(where, requestWindowClause(arg(*filter.UserID)))
+	}
+	if filter.Sender != "" {
+		where = append(where, `LOWER(m.sender_username) = LOWER(`+arg(filter.Sender)+`)`)
+	}
+	if filter.From != nil {
+		where = append(where, `m.message_timestamp >= `+arg(*filter.From))
+	}
+	if filter.To != nil {
+		where = append(where, `m.message_timestamp < `+arg(*filter.To))
+	}
+
+	query := `
+		SELECT ` + storedMessageColumns + `
+		FROM messages m
+		JOIN listeners l ON l.id = m.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY m.message_timestamp ASC, m.id ASC;`
+
+	ctx, cancel := context.WithCancel(ctx)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("mesajlar dışa aktarılırken hata: %w", err)
+	}
+	// Closing drains the remaining rows, cancelling first stops the query on the server instead
+	defer func() {
+		cancel()
+		rows.Close()
+	}()
+
+	for rows.Next() {
+		msg, err := scanStoredMessage(rows)
+		if err != nil {
+			return fmt.Errorf("mesaj satırı okunurken hata: %w", err)
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("mesajlar dışa aktarılırken hata: %w", err)
+	}
+	return nil
+}
@@ -7,23 +7,21 @@ import (
 	"fmt"
 	"kick-chat/domain"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-func (r *Repository) InsertListener(ctx context.Context, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, newIsActive bool, newEndTime *time.Time, newDuration int) (uuid.UUID, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("transaction error: %w", err)
-	}
-	defer tx.Rollback() // Rollback on error or before successful commit
-
+// upsertListener creates the streamer and the user's listeners row if needed, or
+// widens the existing row; the caller commits tx
+func upsertListener(ctx context.Context, tx *sql.Tx, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, newIsActive bool, newEndTime *time.Time, newDuration int) (uuid.UUID, error) {
 	var streamerID uuid.UUID
 	var listenerID uuid.UUID
 
 	// 1. Check if streamer exists, create if not
-	err = tx.QueryRowContext(ctx, "SELECT id FROM streamers WHERE username = $1", streamerUsername).Scan(&streamerID)
+	err := tx.QueryRowContext(ctx, "SELECT id FROM streamers WHERE username = $1", streamerUsername).Scan(&streamerID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// Streamer does not exist, create a new one with provided kickUserID and profilePic
@@ -83,7 +81,7 @@ func (r *Repository) InsertListener(ctx context.Context, streamerUsername string
 				return uuid.Nil, fmt.Errorf("failed to insert new listener: %w", err)
 			}
 			log.Printf("New listener created for user %s and streamer %s with ID: %s\n", userID, streamerUsername, listenerID)
-			return listenerID, tx.Commit() // Commit immediately if new listener created
+			return listenerID, nil
 		}
 		return uuid.Nil, fmt.Errorf("failed to query existing listener: %w", err)
 	}
@@ -132,7 +130,7 @@ func (r *Repository) InsertListener(ctx context.Context, streamerUsername string
 
 	log.Printf("Listener %s updated for user %s and streamer %s.\n", listenerID, userID, streamerUsername)
 
-	return listenerID, tx.Commit()
+	return listenerID, nil
 }
 
 func (r *Repository) InsertUserListenerRequest(listenerID uuid.UUID, userID uuid.UUID, requestTime time.Time, endTime time.Time) error {
@@ -279,13 +277,47 @@ func (r *Repository) GetUserRequestsForListener(listenerID uuid.UUID) ([]struct
 // GÜNCELLENDİ: InsertMessage fonksiyonu link bilgilerini de kaydediyor
 func (r *Repository) InsertMessage(listenerID uuid.UUID, senderUsername, content string, timestamp time.Time, hasLink bool, extractedLinks []string) error {
 	query := `INSERT INTO messages (listener_id, sender_username, content, message_timestamp, has_link, extracted_links) VALUES ($1, $2, $3, $4, $5, $6);`
-	_, err := r.db.Exec(query, listenerID, senderUsername, content, timestamp, hasLink, extractedLinks)
+	_, err := r.db.Exec(query, listenerID, senderUsername, content, timestamp, hasLink, pq.Array(extractedLinks))
 	if err != nil {
 		return fmt.Errorf("mesaj kaydedilirken hata: %w", err)
 	}
 	return nil
 }
 
+// InsertChatEvent, mesaj dışındaki chatroom event'lerini ham payload ile saklar
+func (r *Repository) InsertChatEvent(listenerID uuid.UUID, eventType, kickEventID string, payload []byte, occurredAt time.Time) error {
+	query := `INSERT INTO chat_events (listener_id, event_type, kick_event_id, payload, occurred_at) VALUES ($1, $2, NULLIF($3, ''), $4, $5);`
+	_, err := r.db.Exec(query, listenerID, eventType, kickEventID, payload, occurredAt)
+	if err != nil {
+		return fmt.Errorf("event kaydedilirken hata: %w", err)
+	}
+	return nil
+}
+
+// InsertConnectionAttempts writes a batch of reconnects with one multi-row INSERT
+func (r *Repository) InsertConnectionAttempts(ctx context.Context, attempts []domain.ConnectionAttempt) error {
+	if len(attempts) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO listener_connection_attempts (listener_id, scope, attempt, backoff_ms, reason, attempted_at) VALUES `)
+	args := make([]interface{}, 0, len(attempts)*6)
+	for i, a := range attempts {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		n := len(args)
+		fmt.Fprintf(&sb, "($%d, $%d, $%d, $%d, NULLIF($%d, ''), $%d)", n+1, n+2, n+3, n+4, n+5, n+6)
+		args = append(args, a.ListenerID, a.Scope, a.Attempt, a.Delay.Milliseconds(), a.Reason, a.AttemptedAt)
+	}
+
+	if _, err := r.db.ExecContext(ctx, sb.String(), args...); err != nil {
+		return fmt.Errorf("bağlantı denemeleri kaydedilirken hata: %w", err)
+	}
+	return nil
+}
+
 // YENİ: Listener durumunu güncellemek için
 func (r *Repository) UpdateListenerStatus(listenerID uuid.UUID, isActive bool) error {
 	query := `UPDATE listeners SET is_active = $1, updated_at = NOW() WHERE id = $2;`
@@ -348,7 +380,7 @@ func (r *Repository) GetMessagesByListener(listenerID uuid.UUID, limit, offset i
 		}
 
 		// PostgreSQL array'ini Go slice'ına çevirmek için pq.Array kullanın
-		if err := rows.Scan(&msg.ID, &msg.SenderUsername, &msg.Content, &msg.MessageTimestamp, &msg.HasLink, &msg.ExtractedLinks); err != nil {
+		if err := rows.Scan(&msg.ID, &msg.SenderUsername, &msg.Content, &msg.MessageTimestamp, &msg.HasLink, pq.Array(&msg.ExtractedLinks)); err != nil {
 			log.Printf("Mesaj satırı okunurken hata: %v", err)
 			continue
 		}
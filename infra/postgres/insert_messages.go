@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	messageInsertColumns = 9
+	// Postgres caps a statement at 65535 bind parameters
+	maxMessagesPerStatement = 65535 / messageInsertColumns
+)
+
+// InsertMessages writes a batch of chat messages with multi-row INSERTs in one transaction.
+// Messages already stored (same kick_message_id) are skipped so retries are idempotent.
+// The open requests on each message's streamer count the new rows in the same transaction.
+func (r *Repository) InsertMessages(ctx context.Context, messages []domain.ChatMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return r.wrapMessageError("transaction error", err)
+	}
+	defer tx.Rollback()
+
+	var listenerIDs []string
+	var timestamps []time.Time
+	for start := 0; start < len(messages); start += maxMessagesPerStatement {
+		end := min(start+maxMessagesPerStatement, len(messages))
+		query, args := buildMessagesInsert(messages[start:end])
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return r.wrapMessageError("mesajlar kaydedilirken hata", err)
+		}
+		for rows.Next() {
+			var listenerID string
+			var timestamp time.Time
+			if err := rows.Scan(&listenerID, &timestamp); err != nil {
+				rows.Close()
+				return r.wrapMessageError("kaydedilen mesajlar okunurken hata", err)
+			}
+			listenerIDs = append(listenerIDs, listenerID)
+			timestamps = append(timestamps, timestamp)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return r.wrapMessageError("mesajlar kaydedilirken hata", err)
+		}
+	}
+
+	if len(listenerIDs) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE user_listener_requests r
+			SET message_count = r.message_count + c.n
+			FROM (
+				SELECT rr.id, COUNT(*) AS n
+				FROM unnest($1::uuid[], $2::timestamptz[]) AS m(listener_id, message_timestamp)
+				JOIN listeners ml ON ml.id = m.listener_id
+				JOIN listeners rl ON rl.streamer_id = ml.streamer_id
+				JOIN user_listener_requests rr ON rr.listener_id = rl.id
+				WHERE rr.end_time > NOW() AND m.message_timestamp >= rr.request_time
+				GROUP BY rr.id
+			) c
+			WHERE r.id = c.id;`, pq.Array(listenerIDs), pq.Array(timestamps)); err != nil {
+			return r.wrapMessageError("istek mesaj sayıları güncellenirken hata", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return r.wrapMessageError("commit error", err)
+	}
+	return nil
+}
+
+func buildMessagesInsert(messages []domain.ChatMessage) (string, []any) {
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO messages (listener_id, kick_message_id, sender_id, sender_username, sender_color, content, message_timestamp, has_link, extracted_links) VALUES `)
+
+	args := make([]any, 0, len(messages)*messageInsertColumns)
+	for i, m := range messages {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		base := i * messageInsertColumns
+		fmt.Fprintf(&sb, "($%d, NULLIF($%d, ''), NULLIF($%d, 0), $%d, NULLIF($%d, ''), $%d, $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9)
+		args = append(args,
+			m.ListenerID,
+			m.KickMessageID,
+			m.SenderID,
+			m.SenderUsername,
+			m.SenderColor,
+			m.Content,
+			m.Timestamp,
+			m.HasLink,
+			pq.Array(m.ExtractedLinks),
+		)
+	}
+	sb.WriteString(" ON CONFLICT (kick_message_id) WHERE kick_message_id IS NOT NULL DO NOTHING RETURNING listener_id, message_timestamp")
+	return sb.String(), args
+}
+
+func (r *Repository) wrapMessageError(msg string, err error) error {
+	if r.isTransientError(err) {
+		return fmt.Errorf("%s: %w: %w", msg, domain.ErrTransient, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// OpenListenRequest upserts the user's listeners row for streamerUsername and saves
+// their request on it in one transaction, so a request the quota turns away
+// leaves no listener or streamer row behind. It returns the listener ID.
+func (r *Repository) OpenListenRequest(ctx context.Context, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, requestTime, endTime time.Time, quota time.Duration) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	listenerID, err := upsertListener(ctx, tx, streamerUsername, kickUserID, profilePic, userID,
+		true, &endTime, int(endTime.Sub(requestTime).Seconds()))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("listener oluşturulamadı: %w", err)
+	}
+	if quota > 0 {
+		if err := checkListenQuota(ctx, tx, listenerID, userID, endTime, quota); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if _, err := saveListenRequest(ctx, tx, listenerID, userID, requestTime, endTime); err != nil {
+		return uuid.Nil, err
+	}
+
+	return listenerID, tx.Commit()
+}
+
+// SaveListenRequest sets the end time of the user's open request on listenerID,
+// creating it if needed, and mirrors the window onto the user's listeners row
+// so both tables always agree. A non-zero quota caps the user's open listening
+// time across streamers; the user row is locked so concurrent requests cannot
+// both fit into the same remaining quota.
+func (r *Repository) SaveListenRequest(ctx context.Context, listenerID, userID uuid.UUID, requestTime, endTime time.Time, quota time.Duration) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	if quota > 0 {
+		if err := checkListenQuota(ctx, tx, listenerID, userID, endTime, quota); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	requestID, err := saveListenRequest(ctx, tx, listenerID, userID, requestTime, endTime)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return requestID, tx.Commit()
+}
+
+func saveListenRequest(ctx context.Context, tx *sql.Tx, listenerID, userID uuid.UUID, requestTime, endTime time.Time) (uuid.UUID, error) {
+	var requestID uuid.UUID
+	err := tx.QueryRowContext(ctx, `
+		UPDATE user_listener_requests
+		SET end_time = $3, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM user_listener_requests
+			WHERE listener_id = $1 AND user_id = $2 AND end_time > NOW()
+			ORDER BY end_time DESC
+			LIMIT 1
+		)
+		RETURNING id, request_time;`, listenerID, userID, endTime).Scan(&requestID, &requestTime)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO user_listener_requests (listener_id, user_id, request_time, end_time)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id;`, listenerID, userID, requestTime, endTime).Scan(&requestID)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("dinleme isteği kaydedilemedi: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE listeners
+		SET end_time = $2, duration = $3, is_active = $2 > NOW(), updated_at = NOW()
+		WHERE id = $1;`, listenerID, endTime, int(endTime.Sub(requestTime).Seconds()))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("dinleyici süresi güncellenemedi: %w", err)
+	}
+	return requestID, nil
+}
+
+// checkListenQuota counts the user's open time on other streamers with the user row locked
+func checkListenQuota(ctx context.Context, tx *sql.Tx, listenerID, userID uuid.UUID, endTime time.Time, quota time.Duration) error {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE;`, userID); err != nil {
+		return fmt.Errorf("kullanıcı kilitlenemedi: %w", err)
+	}
+
+	var seconds float64
+	err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (r.end_time - NOW()))), 0)
+		FROM user_listener_requests r
+		JOIN listeners l ON l.id = r.listener_id
+		WHERE r.user_id = $1 AND r.end_time > NOW()
+			AND l.streamer_id <> (SELECT streamer_id FROM listeners WHERE id = $2);`, userID, listenerID).Scan(&seconds)
+	if err != nil {
+		return fmt.Errorf("kullanıcının dinleme süresi hesaplanamadı: %w", err)
+	}
+
+	reserved := time.Duration(seconds * float64(time.Second))
+	requested := time.Until(endTime)
+	if reserved+requested > quota {
+		left := max(quota-reserved, 0)
+		return fmt.Errorf("%w: kalan kota %s, istenen %s", domain.ErrQuotaExceeded,
+			left.Truncate(time.Second), requested.Truncate(time.Second))
+	}
+	return nil
+}
+
+// GetUserActiveRequest returns the user's open request for streamer or domain.ErrNotFound
+func (r *Repository) GetUserActiveRequest(ctx context.Context, userID uuid.UUID, streamer string) (*domain.ListenRequest, error) {
+	query := `
+		SELECT r.id, r.listener_id, r.user_id, s.username, r.request_time, r.end_time, l.sinks
+		FROM user_listener_requests r
+		JOIN listeners l ON l.id = r.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE r.user_id = $1 AND s.username = $2 AND r.end_time > NOW()
+		ORDER BY r.end_time DESC
+		LIMIT 1;`
+
+	var req domain.ListenRequest
+	err := r.db.QueryRowContext(ctx, query, userID, streamer).Scan(
+		&req.ID, &req.ListenerID, &req.UserID, &req.Streamer, &req.RequestTime, &req.EndTime, pq.Array(&req.Sinks))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dinleme isteği okunamadı: %w", err)
+	}
+	return &req, nil
+}
+
+// SetListenerSinks stores the sinks a user's listener row asked for, nil resets to all
+func (r *Repository) SetListenerSinks(ctx context.Context, listenerID uuid.UUID, sinks []string) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE listeners SET sinks = $2, updated_at = NOW() WHERE id = $1;`,
+		listenerID, pq.Array(sinks)); err != nil {
+		return fmt.Errorf("dinleyici çıktıları güncellenirken hata: %w", err)
+	}
+	return nil
+}
+
+// EndListenRequest closes the user's open request on listenerID now and
+// deactivates their listeners row; other users' rows are untouched
+func (r *Repository) EndListenRequest(ctx context.Context, listenerID, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_listener_requests
+		SET end_time = NOW(), updated_at = NOW()
+		WHERE listener_id = $1 AND user_id = $2 AND end_time > NOW();`, listenerID, userID)
+	if err != nil {
+		return fmt.Errorf("dinleme isteği sonlandırılamadı: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE listeners
+		SET is_active = false, end_time = NOW(), updated_at = NOW()
+		WHERE id = $1 AND user_id = $2;`, listenerID, userID)
+	if err != nil {
+		return fmt.Errorf("dinleyici pasif yapılamadı: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetUserListeners lists the user's open requests. MessageCount covers every
+// message stored for the streamer since the request started, whichever row holds
+// it; InsertMessages keeps the counter so listing never scans messages.
+func (r *Repository) GetUserListeners(ctx context.Context, userID uuid.UUID) ([]domain.UserListener, error) {
+	query := `
+		SELECT r.id, r.listener_id, s.username, COALESCE(s.profile_pic, ''), r.request_time, r.end_time, l.is_active,
+			l.state, l.state_changed_at, COALESCE(l.last_error, ''), r.message_count
+		FROM user_listener_requests r
+		JOIN listeners l ON l.id = r.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE r.user_id = $1 AND r.end_time > NOW()
+		ORDER BY r.end_time ASC;`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("kullanıcının dinleyicileri getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	listeners := []domain.UserListener{}
+	for rows.Next() {
+		var l domain.UserListener
+		if err := rows.Scan(&l.RequestID, &l.ListenerID, &l.Streamer, &l.ProfilePic,
+			&l.RequestTime, &l.EndTime, &l.IsActive, &l.State, &l.StateChangedAt, &l.LastError, &l.MessageCount); err != nil {
+			return nil, fmt.Errorf("dinleyici satırı okunurken hata: %w", err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, rows.Err()
+}
+
+// UpdateListenerState records a state transition. Transitions are written from
+// goroutines, so an older change never overwrites a newer one.
+func (r *Repository) UpdateListenerState(ctx context.Context, listenerIDs []uuid.UUID, state string, changedAt time.Time, lastError string) error {
+	query := `
+		UPDATE listeners
+		SET state = $2,
+			state_changed_at = $3,
+			last_error = COALESCE(NULLIF($4, ''), last_error),
+			is_active = CASE
+				WHEN $2 IN ('expired', 'failed', 'stopped') THEN false
+				WHEN $2 = 'subscribed' THEN true
+				ELSE is_active
+			END,
+			updated_at = NOW()
+		WHERE id = ANY($1) AND (state_changed_at IS NULL OR state_changed_at <= $3);`
+
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(listenerIDs), state, changedAt, lastError); err != nil {
+		return fmt.Errorf("dinleyici durumu güncellenirken hata: %w", err)
+	}
+	return nil
+}
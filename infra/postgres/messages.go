@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// storedMessageColumns must stay in sync with scanStoredMessage
+const storedMessageColumns = `
+	m.id, m.listener_id, s.username, COALESCE(m.kick_message_id, ''), COALESCE(m.sender_id, 0),
+	m.sender_username, COALESCE(m.sender_color, ''), m.content, m.message_timestamp, m.has_link, m.extracted_links`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanStoredMessage(row rowScanner, extra ...any) (domain.StoredMessage, error) {
+	var msg domain.StoredMessage
+	dest := []any{
+		&msg.ID, &msg.ListenerID, &msg.Streamer, &msg.KickMessageID, &msg.SenderID,
+		&msg.SenderUsername, &msg.SenderColor, &msg.Content, &msg.Timestamp, &msg.HasLink,
+		pq.Array(&msg.ExtractedLinks),
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return msg, err
+}
+
+// GetMessagesAfterKickID returns messages of the given streamers stored after the
+// message with kickMessageID, oldest first. Used to resume live feeds.
+func (r *Repository) GetMessagesAfterKickID(ctx context.Context, streamers []string, kickMessageID string, limit int) ([]domain.StoredMessage, error) {
+	query := `
+		WITH anchor AS (
+			SELECT message_timestamp, id FROM messages WHERE kick_message_id = $1
+		)
+		SELECT ` + storedMessageColumns + `
+		FROM messages m
+		JOIN listeners l ON l.id = m.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		JOIN anchor a ON (m.message_timestamp, m.id) > (a.message_timestamp, a.id)
+		WHERE s.username = ANY($2)
+		ORDER BY m.message_timestamp ASC, m.id ASC
+		LIMIT $3;`
+
+	rows, err := r.db.QueryContext(ctx, query, kickMessageID, pq.Array(streamers), limit)
+	if err != nil {
+		return nil, fmt.Errorf("mesajlar getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.StoredMessage
+	for rows.Next() {
+		msg, err := scanStoredMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("mesaj satırı okunurken hata: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// GetUserStreamers lists streamers the user currently has a listener or an open request for
+func (r *Repository) GetUserStreamers(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT s.username
+		FROM listeners l
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE l.user_id = $1 AND l.is_active = true AND (l.end_time IS NULL OR l.end_time > NOW())
+		UNION
+		SELECT s.username
+		FROM user_listener_requests r
+		JOIN listeners l ON l.id = r.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE r.user_id = $1 AND r.end_time > NOW();`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("kullanıcının yayıncıları getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var streamers []string
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
+		}
+		streamers = append(streamers, username)
+	}
+	return streamers, rows.Err()
+}
+
+// CanReadListener reports whether the user owns the listener or has requested the
+// same streamer. Messages are stored against whichever listener row came first,
+// so access is checked per streamer rather than per row; the message queries then
+// only return what arrived during the user's requests, see requestWindowClause.
+func (r *Repository) CanReadListener(ctx context.Context, userID, listenerID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM listeners target
+			JOIN listeners own ON own.streamer_id = target.streamer_id
+			LEFT JOIN user_listener_requests r ON r.listener_id = own.id AND r.user_id = $1
+			WHERE target.id = $2 AND (own.user_id = $1 OR r.id IS NOT NULL)
+		), EXISTS (SELECT 1 FROM listeners WHERE id = $2);`
+
+	var allowed, exists bool
+	if err := r.db.QueryRowContext(ctx, query, userID, listenerID).Scan(&allowed, &exists); err != nil {
+		return false, fmt.Errorf("dinleyici yetkisi kontrol edilirken hata: %w", err)
+	}
+	if !exists {
+		return false, domain.ErrNotFound
+	}
+	return allowed, nil
+}
+
+// requestWindowClause keeps messages that arrived while the user given by the
+// placeholder user had a request open on their streamer. Other users' listeners
+// keep storing a streamer after the user's request ended, those messages were
+// never requested by them.
+func requestWindowClause(user string) string {
+	return `EXISTS (
+			SELECT 1
+			FROM user_listener_requests r
+			JOIN listeners rl ON rl.id = r.listener_id
+			WHERE r.user_id = ` + user + ` AND rl.streamer_id = l.streamer_id
+				AND m.message_timestamp BETWEEN r.request_time AND r.end_time
+		)`
+}
+
+// GetMessages pages through the messages of the listener's streamer using a
+// (message_timestamp, id) keyset, which stays fast and stable while new rows arrive
+func (r *Repository) GetMessages(ctx context.Context, filter domain.MessageFilter) ([]domain.StoredMessage, error) {
+	args := []any{filter.ListenerID}
+	where := []string{`l.streamer_id = (SELECT streamer_id FROM listeners WHERE id = $1)`}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where = append(where, requestWindowClause(arg(filter.UserID)))
+
+	if filter.Sender != "" {
+		where = append(where, `LOWER(m.sender_username) = LOWER(`+arg(filter.Sender)+`)`)
+	}
+	if filter.From != nil {
+		where = append(where, `m.message_timestamp >= `+arg(*filter.From))
+	}
+	if filter.To != nil {
+		where = append(where, `m.message_timestamp < `+arg(*filter.To))
+	}
+	if filter.HasLink != nil {
+		where = append(where, `m.has_link = `+arg(*filter.HasLink))
+	}
+	if filter.Contains != "" {
+		where = append(where, `m.content ILIKE `+arg("%"+escapeLike(filter.Contains)+"%"))
+	}
+
+	order, cmp := "DESC", "<"
+	if filter.Ascending {
+		order, cmp = "ASC", ">"
+	}
+	if filter.After != nil {
+		ts, id := arg(filter.After.Timestamp), arg(filter.After.ID)
+		where = append(where, `(m.message_timestamp, m.id) `+cmp+` (`+ts+`, `+id+`)`)
+	}
+
+	query := `
+		SELECT ` + storedMessageColumns + `
+		FROM messages m
+		JOIN listeners l ON l.id = m.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY m.message_timestamp ` + order + `, m.id ` + order + `
+		LIMIT ` + arg(filter.Limit) + `;`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mesajlar getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.StoredMessage
+	for rows.Next() {
+		msg, err := scanStoredMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("mesaj satırı okunurken hata: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes user input match literally inside a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_listener_requests;
+DROP TABLE IF EXISTS listeners;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS streamers;
@@ -0,0 +1,43 @@
+-- Tables that used to be created by initDB, kept idempotent so existing databases adopt them
+CREATE TABLE IF NOT EXISTS streamers (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	username VARCHAR(50) NOT NULL UNIQUE, -- Yayıncının kullanıcı adı (StreamerUsername)
+	kick_user_id INT UNIQUE, -- Kick platformundaki kullanıcı ID'si
+	profile_pic TEXT, -- Profil fotoğrafı URL'si veya bilgisi
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	username VARCHAR(50) NOT NULL UNIQUE,
+	email VARCHAR(100) NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	failed_login_attempts INT DEFAULT 0,
+	last_login TIMESTAMP WITH TIME ZONE,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS listeners (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	streamer_id UUID REFERENCES streamers(id) ON DELETE CASCADE,
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	is_active BOOLEAN DEFAULT true NOT NULL,
+	end_time TIMESTAMP WITH TIME ZONE NULL,
+	duration INT DEFAULT 0 NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	UNIQUE(streamer_id, user_id) -- Bir kullanıcının aynı yayıncıyı birden fazla dinlemesini engeller
+);
+
+CREATE TABLE IF NOT EXISTS user_listener_requests (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	listener_id UUID NOT NULL,
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	request_time TIMESTAMP WITH TIME ZONE NOT NULL,
+	end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	FOREIGN KEY (listener_id) REFERENCES listeners(id) ON DELETE CASCADE
+);
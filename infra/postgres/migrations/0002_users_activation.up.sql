@@ -0,0 +1,3 @@
+-- cleanupExpiredActivations deletes by these columns; existing users count as activated
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_active BOOLEAN DEFAULT true NOT NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS activation_expiry TIMESTAMP WITH TIME ZONE;
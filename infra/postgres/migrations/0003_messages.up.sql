@@ -0,0 +1,22 @@
+-- Dinlenen sohbet mesajları, kick_message_id ile tekilleştirilir
+CREATE TABLE IF NOT EXISTS messages (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	listener_id UUID NOT NULL REFERENCES listeners(id) ON DELETE CASCADE,
+	kick_message_id TEXT,
+	sender_id INT,
+	sender_username VARCHAR(50) NOT NULL,
+	sender_color VARCHAR(16),
+	content TEXT NOT NULL,
+	message_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+	has_link BOOLEAN DEFAULT false NOT NULL,
+	extracted_links TEXT[],
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Databases created before kick_message_id existed only have the original columns
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS kick_message_id TEXT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS sender_id INT;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS sender_color VARCHAR(16);
+
+CREATE INDEX IF NOT EXISTS idx_messages_listener_time ON messages (listener_id, message_timestamp DESC, id DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_kick_message_id ON messages (kick_message_id) WHERE kick_message_id IS NOT NULL;
@@ -0,0 +1,12 @@
+-- Chat mesajı dışındaki Kick event'leri (ban, silme, abonelik, anket...)
+CREATE TABLE IF NOT EXISTS chat_events (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	listener_id UUID NOT NULL REFERENCES listeners(id) ON DELETE CASCADE,
+	event_type VARCHAR(50) NOT NULL,
+	kick_event_id TEXT,
+	payload JSONB NOT NULL,
+	occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_events_listener_time ON chat_events (listener_id, occurred_at DESC);
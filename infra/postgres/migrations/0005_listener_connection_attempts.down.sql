@@ -0,0 +1 @@
+DROP TABLE IF EXISTS listener_connection_attempts;
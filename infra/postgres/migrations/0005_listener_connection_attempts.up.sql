@@ -0,0 +1,12 @@
+-- Her yeniden bağlanma denemesi, sürekli kopan kanalları görebilmek için
+CREATE TABLE IF NOT EXISTS listener_connection_attempts (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	listener_id UUID NOT NULL REFERENCES listeners(id) ON DELETE CASCADE,
+	scope VARCHAR(20) NOT NULL,
+	attempt INT NOT NULL,
+	backoff_ms BIGINT NOT NULL,
+	reason TEXT,
+	attempted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_connection_attempts_listener_time ON listener_connection_attempts (listener_id, attempted_at DESC);
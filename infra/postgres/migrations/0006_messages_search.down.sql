@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_messages_timestamp;
+DROP INDEX IF EXISTS idx_messages_search_vector;
+DROP INDEX IF EXISTS idx_messages_search_pending;
+DROP TRIGGER IF EXISTS messages_search_vector_update ON messages;
+DROP FUNCTION IF EXISTS messages_search_vector_update();
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
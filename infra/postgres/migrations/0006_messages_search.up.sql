@@ -0,0 +1,26 @@
+-- migrate:no-transaction
+-- Tam metin arama: Türkçe ve İngilizce kökleri aynı vektörde tutulur.
+-- Sütun boş eklenir ve indeksler CONCURRENTLY kurulur, büyük bir messages tablosu
+-- açılışta yeniden yazılıp kilitlenmez. Eski satırları uygulama parça parça doldurur.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION messages_search_vector_update() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector := to_tsvector('turkish', coalesce(NEW.content, '')) || to_tsvector('english', coalesce(NEW.content, ''));
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS messages_search_vector_update ON messages;
+CREATE TRIGGER messages_search_vector_update BEFORE INSERT OR UPDATE OF content ON messages
+	FOR EACH ROW EXECUTE FUNCTION messages_search_vector_update();
+
+-- Yarıda kalan bir CONCURRENTLY kurulumu INVALID bir indeks bırakır ve IF NOT EXISTS
+-- onu geçerli sayardı; bu yüzden her indeks yeniden denemede silinip baştan kurulur.
+-- Doldurulmayı bekleyen satırlar; doldurma bitince boş kalır
+DROP INDEX CONCURRENTLY IF EXISTS idx_messages_search_pending;
+CREATE INDEX CONCURRENTLY idx_messages_search_pending ON messages (id) WHERE search_vector IS NULL;
+DROP INDEX CONCURRENTLY IF EXISTS idx_messages_search_vector;
+CREATE INDEX CONCURRENTLY idx_messages_search_vector ON messages USING GIN (search_vector);
+DROP INDEX CONCURRENTLY IF EXISTS idx_messages_timestamp;
+CREATE INDEX CONCURRENTLY idx_messages_timestamp ON messages (message_timestamp DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE streamers DROP COLUMN IF EXISTS metadata_updated_at;
+ALTER TABLE streamers DROP COLUMN IF EXISTS chatroom_id;
@@ -0,0 +1,3 @@
+-- Kanal çözümleyicisinin son çare kaynağı: sohbet odası ID'si yayıncıyla birlikte saklanır
+ALTER TABLE streamers ADD COLUMN IF NOT EXISTS chatroom_id INT;
+ALTER TABLE streamers ADD COLUMN IF NOT EXISTS metadata_updated_at TIMESTAMP WITH TIME ZONE;
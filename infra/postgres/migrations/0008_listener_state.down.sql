@@ -0,0 +1,4 @@
+ALTER TABLE listeners DROP CONSTRAINT IF EXISTS listeners_state_check;
+ALTER TABLE listeners DROP COLUMN IF EXISTS last_error;
+ALTER TABLE listeners DROP COLUMN IF EXISTS state_changed_at;
+ALTER TABLE listeners DROP COLUMN IF EXISTS state;
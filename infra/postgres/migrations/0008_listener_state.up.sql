@@ -0,0 +1,11 @@
+-- is_active tek başına ölü bir dinleyiciyi sessiz bir sohbetten ayıramıyordu
+ALTER TABLE listeners ADD COLUMN IF NOT EXISTS state VARCHAR(20) NOT NULL DEFAULT 'pending';
+ALTER TABLE listeners ADD COLUMN IF NOT EXISTS state_changed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE listeners ADD COLUMN IF NOT EXISTS last_error TEXT;
+
+UPDATE listeners SET state = 'stopped', state_changed_at = updated_at WHERE is_active = false;
+
+ALTER TABLE listeners DROP CONSTRAINT IF EXISTS listeners_state_check;
+ALTER TABLE listeners ADD CONSTRAINT listeners_state_check CHECK (state IN (
+	'pending', 'resolving', 'connecting', 'subscribed', 'reconnecting', 'paused', 'expired', 'failed', 'stopped'
+));
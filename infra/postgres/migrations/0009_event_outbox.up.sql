@@ -0,0 +1,9 @@
+-- Broker kapalıyken yayınlanamayan olaylar burada sırayla bekler
+CREATE TABLE IF NOT EXISTS event_outbox (
+	id BIGSERIAL PRIMARY KEY,
+	routing_key VARCHAR(255) NOT NULL,
+	payload JSONB NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE listeners DROP COLUMN IF EXISTS sinks;
@@ -0,0 +1,2 @@
+-- NULL: dağıtımda tanımlı tüm çıktılar
+ALTER TABLE listeners ADD COLUMN IF NOT EXISTS sinks TEXT[];
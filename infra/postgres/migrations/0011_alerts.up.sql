@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	streamer VARCHAR(50), -- NULL: tüm kanallar
+	kind VARCHAR(20) NOT NULL CHECK (kind IN ('keyword', 'regex', 'link')),
+	pattern TEXT NOT NULL,
+	cooldown_seconds INT NOT NULL DEFAULT 60,
+	enabled BOOLEAN NOT NULL DEFAULT true,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_user ON alert_rules (user_id);
+
+CREATE TABLE IF NOT EXISTS alerts (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	streamer VARCHAR(50) NOT NULL,
+	kick_message_id VARCHAR(100),
+	sender_username VARCHAR(50) NOT NULL,
+	content TEXT NOT NULL,
+	matched TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_user_created ON alerts (user_id, created_at DESC, id DESC);
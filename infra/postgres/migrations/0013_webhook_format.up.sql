@@ -0,0 +1,3 @@
+-- json: imzalı ham olay, discord / slack: kanala hazır mesaj, kısa süre içindeki olaylar tek gönderide toplanır
+ALTER TABLE webhook_endpoints
+	ADD COLUMN IF NOT EXISTS format VARCHAR(20) NOT NULL DEFAULT 'json' CHECK (format IN ('json', 'discord', 'slack'));
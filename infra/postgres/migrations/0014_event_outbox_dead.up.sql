@@ -0,0 +1,10 @@
+-- Broker'ın defalarca reddettiği olaylar; outbox'ın başını tıkamasınlar diye buraya taşınır
+CREATE TABLE IF NOT EXISTS event_outbox_dead (
+	id BIGINT PRIMARY KEY,
+	routing_key VARCHAR(255) NOT NULL,
+	payload JSONB NOT NULL,
+	attempts INTEGER NOT NULL,
+	last_error TEXT,
+	created_at TIMESTAMP WITH TIME ZONE,
+	dead_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE user_listener_requests DROP COLUMN IF EXISTS message_count;
@@ -0,0 +1,15 @@
+-- İstek başladığından beri yayıncıya gelen mesaj sayısı, mesaj yazılırken artırılır.
+-- Sabit varsayılanlı sütun eklemek tabloyu yeniden yazmaz.
+ALTER TABLE user_listener_requests ADD COLUMN IF NOT EXISTS message_count BIGINT NOT NULL DEFAULT 0;
+
+-- Açık istekler o ana kadar gelen mesajlarla başlar, yoksa süreleri boyunca eksik sayarlar.
+-- Biten isteklerin sayısı hiç okunmaz.
+UPDATE user_listener_requests r
+SET message_count = (
+	SELECT COUNT(*)
+	FROM messages m
+	JOIN listeners ml ON ml.id = m.listener_id
+	JOIN listeners rl ON rl.streamer_id = ml.streamer_id
+	WHERE rl.id = r.listener_id AND m.message_timestamp >= r.request_time
+)
+WHERE r.end_time > NOW();
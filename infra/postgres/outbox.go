@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (r *Repository) InsertOutboxEvents(ctx context.Context, events []domain.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO event_outbox (routing_key, payload, created_at) VALUES `)
+	args := make([]interface{}, 0, len(events)*3)
+	for i, e := range events {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		// created_at is when the event happened, not when it was parked
+		createdAt := e.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		fmt.Fprintf(&sb, "($%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3)
+		args = append(args, e.RoutingKey, string(e.Payload), createdAt)
+	}
+
+	if _, err := r.db.ExecContext(ctx, sb.String(), args...); err != nil {
+		return r.wrapMessageError("outbox'a yazılırken hata", err)
+	}
+	return nil
+}
+
+// HasOutboxEvents reports whether any row is waiting, including rows another
+// replica is draining right now
+func (r *Repository) HasOutboxEvents(ctx context.Context) (bool, error) {
+	var pending bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM event_outbox);`).Scan(&pending); err != nil {
+		return false, fmt.Errorf("outbox okunurken hata: %w", err)
+	}
+	return pending, nil
+}
+
+// DrainOutbox locks up to limit of the oldest rows and hands them to publish.
+// They are deleted when publish succeeds and kept with the error otherwise;
+// SKIP LOCKED lets every replica drain the table at once without doubles.
+// Only rows the broker rejected count an attempt, an unreachable broker says
+// nothing about the event. After maxAttempts rejections a row moves to
+// event_outbox_dead so it no longer holds back the rows behind it.
+// It returns how many rows were published and how many were dead-lettered.
+func (r *Repository) DrainOutbox(ctx context.Context, limit, maxAttempts int, publish func([]domain.OutboxEvent) error) (int, int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("transaction error: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, routing_key, payload, created_at, attempts
+		FROM event_outbox
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED;`, limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("outbox okunurken hata: %w", err)
+	}
+
+	var events []domain.OutboxEvent
+	var ids []int64
+	for rows.Next() {
+		var e domain.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.RoutingKey, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("outbox satırı okunurken hata: %w", err)
+		}
+		events = append(events, e)
+		ids = append(ids, e.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	if len(events) == 0 {
+		return 0, 0, nil
+	}
+
+	publishErr := publish(events)
+	var rejected *domain.RejectedEventsError
+	var rejectedIDs []int64
+	switch {
+	case publishErr == nil:
+	case errors.As(publishErr, &rejected):
+		// The rest of the batch was confirmed and is deleted below
+		for _, i := range rejected.Indexes {
+			rejectedIDs = append(rejectedIDs, ids[i])
+		}
+		ids = slices.DeleteFunc(ids, func(id int64) bool { return slices.Contains(rejectedIDs, id) })
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE event_outbox SET attempts = attempts + 1, last_error = $2
+			WHERE id = ANY($1);`, pq.Array(rejectedIDs), publishErr.Error()); err != nil {
+			return 0, 0, fmt.Errorf("outbox hatası kaydedilemedi: %w", err)
+		}
+	default:
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE event_outbox SET last_error = $2
+			WHERE id = ANY($1);`, pq.Array(ids), publishErr.Error()); err != nil {
+			return 0, 0, fmt.Errorf("outbox hatası kaydedilemedi: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, 0, err
+		}
+		return 0, 0, publishErr
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_outbox WHERE id = ANY($1);`, pq.Array(ids)); err != nil {
+		return 0, 0, fmt.Errorf("outbox temizlenirken hata: %w", err)
+	}
+	var dead int64
+	if len(rejectedIDs) > 0 {
+		result, err := tx.ExecContext(ctx, `
+			WITH dead AS (
+				DELETE FROM event_outbox
+				WHERE id = ANY($1) AND attempts >= $2
+				RETURNING id, routing_key, payload, attempts, last_error, created_at
+			)
+			INSERT INTO event_outbox_dead (id, routing_key, payload, attempts, last_error, created_at)
+			SELECT id, routing_key, payload, attempts, last_error, created_at FROM dead;`,
+			pq.Array(rejectedIDs), maxAttempts)
+		if err != nil {
+			return 0, 0, fmt.Errorf("reddedilen olaylar taşınamadı: %w", err)
+		}
+		dead, _ = result.RowsAffected()
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return len(ids), int(dead), publishErr
+}
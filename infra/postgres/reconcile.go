@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+
+	"github.com/lib/pq"
+)
+
+// GetDesiredListeners returns every non-expired request, oldest first per streamer
+func (r *Repository) GetDesiredListeners(ctx context.Context) ([]domain.DesiredListener, error) {
+	query := `
+		SELECT r.id, r.listener_id, r.user_id, s.username, r.request_time, r.end_time, l.sinks, l.state, l.state_changed_at
+		FROM user_listener_requests r
+		JOIN listeners l ON l.id = r.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE r.end_time > NOW()
+		ORDER BY s.username, r.request_time ASC;`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("istenen dinleyiciler getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var desired []domain.DesiredListener
+	for rows.Next() {
+		var d domain.DesiredListener
+		if err := rows.Scan(&d.ID, &d.ListenerID, &d.UserID, &d.Streamer, &d.RequestTime, &d.EndTime,
+			pq.Array(&d.Sinks), &d.State, &d.StateChangedAt); err != nil {
+			return nil, fmt.Errorf("istenen dinleyici satırı okunurken hata: %w", err)
+		}
+		desired = append(desired, d)
+	}
+	return desired, rows.Err()
+}
+
+// DeactivateUnrequestedListeners turns off rows left active without an open request
+func (r *Repository) DeactivateUnrequestedListeners(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE listeners l
+		SET is_active = false,
+			state = CASE WHEN l.state IN ('expired', 'failed', 'stopped') THEN l.state ELSE 'expired' END,
+			state_changed_at = NOW(),
+			updated_at = NOW()
+		WHERE l.is_active = true
+			AND NOT EXISTS (
+				SELECT 1 FROM user_listener_requests r
+				WHERE r.listener_id = l.id AND r.end_time > NOW()
+			);`
+
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("istenmeyen dinleyiciler pasif yapılırken hata: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -23,7 +24,8 @@ type Repository struct {
 	db *sql.DB
 }
 
-func NewRepository(connString string) (*Repository, error) {
+// OpenDB connects without touching the schema, used by the migrate CLI
+func OpenDB(connString string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", connString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -32,14 +34,28 @@ func NewRepository(connString string) (*Repository, error) {
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
+	return db, nil
+}
+
+func NewRepository(connString string) (*Repository, error) {
+	db, err := OpenDB(connString)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Println("Connected to PostgreSQL successfully")
 
-	if err := initDB(db); err != nil {
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+	log.Println("Database schema is up to date")
 
 	repo := &Repository{db: db}
 	go repo.startCleanupJob(10 * time.Minute)
+	go repo.backfillSearchVectors(5000, 100*time.Millisecond)
 	return repo, nil
 }
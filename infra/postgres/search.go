@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Matches both stemmers, so "yayınlar" finds "yayın" and "streams" finds "stream"
+const searchQueryExpr = `(websearch_to_tsquery('turkish', $2) || websearch_to_tsquery('english', $2))`
+
+// Content is escaped before ts_headline so the snippet is safe to render as HTML
+const searchSnippetExpr = `
+	ts_headline(
+		CASE WHEN to_tsvector('turkish', m.content) @@ websearch_to_tsquery('turkish', $2) THEN 'turkish' ELSE 'english' END::regconfig,
+		replace(replace(replace(m.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+		CASE WHEN to_tsvector('turkish', m.content) @@ websearch_to_tsquery('turkish', $2)
+			THEN websearch_to_tsquery('turkish', $2) ELSE websearch_to_tsquery('english', $2) END,
+		'StartSel=<mark>, StopSel=</mark>, MaxWords=25, MinWords=8, MaxFragments=2'
+	)`
+
+type searchQuery struct {
+	where string
+	args  []any
+}
+
+// buildSearchQuery returns the shared FROM/WHERE for hits, totals and facets.
+// $1 is always the user and $2 the search text.
+func buildSearchQuery(filter domain.SearchFilter) searchQuery {
+	args := []any{filter.UserID, filter.Query}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{
+		`m.search_vector @@ ` + searchQueryExpr,
+		// Only messages that arrived during one of the user's requests
+		requestWindowClause("$1"),
+	}
+	if len(filter.Streamers) > 0 {
+		where = append(where, `s.username = ANY(`+arg(pq.Array(filter.Streamers))+`)`)
+	}
+	if filter.Sender != "" {
+		where = append(where, `LOWER(m.sender_username) = LOWER(`+arg(filter.Sender)+`)`)
+	}
+	if filter.From != nil {
+		where = append(where, `m.message_timestamp >= `+arg(*filter.From))
+	}
+	if filter.To != nil {
+		where = append(where, `m.message_timestamp < `+arg(*filter.To))
+	}
+
+	return searchQuery{
+		where: `
+		FROM messages m
+		JOIN listeners l ON l.id = m.listener_id
+		JOIN streamers s ON s.id = l.streamer_id
+		WHERE ` + strings.Join(where, " AND "),
+		args: args,
+	}
+}
+
+// SearchMessages runs a ranked full-text search and computes facets over the whole match set
+func (r *Repository) SearchMessages(ctx context.Context, filter domain.SearchFilter) (*domain.SearchResult, error) {
+	q := buildSearchQuery(filter)
+	n := len(q.args)
+
+	hitsQuery := `
+		SELECT ` + storedMessageColumns + `, ts_rank(m.search_vector, ` + searchQueryExpr + `) AS rank, ` + searchSnippetExpr +
+		q.where + `
+		ORDER BY rank DESC, m.message_timestamp DESC, m.id DESC
+		LIMIT $` + fmt.Sprint(n+1) + ` OFFSET $` + fmt.Sprint(n+2) + `;`
+
+	rows, err := r.db.QueryContext(ctx, hitsQuery, append(q.args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("mesaj araması yapılırken hata: %w", err)
+	}
+	defer rows.Close()
+
+	result := &domain.SearchResult{Hits: []domain.SearchHit{}}
+	for rows.Next() {
+		var hit domain.SearchHit
+		msg, err := scanStoredMessage(rows, &hit.Rank, &hit.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("arama sonucu okunurken hata: %w", err)
+		}
+		hit.StoredMessage = msg
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+q.where, q.args...).Scan(&result.Total); err != nil {
+		return nil, fmt.Errorf("arama sonucu sayılırken hata: %w", err)
+	}
+
+	facets := []struct {
+		expr string
+		dest *[]domain.FacetCount
+	}{
+		{`s.username`, &result.Facets.Channels},
+		{`m.sender_username`, &result.Facets.Senders},
+		{`to_char(date_trunc('day', m.message_timestamp), 'YYYY-MM-DD')`, &result.Facets.Days},
+	}
+	for _, facet := range facets {
+		counts, err := r.searchFacet(ctx, q, facet.expr, filter.FacetSize)
+		if err != nil {
+			return nil, err
+		}
+		*facet.dest = counts
+	}
+	return result, nil
+}
+
+func (r *Repository) searchFacet(ctx context.Context, q searchQuery, expr string, size int) ([]domain.FacetCount, error) {
+	query := `SELECT ` + expr + ` AS value, COUNT(*) AS count` + q.where + `
+		GROUP BY value
+		ORDER BY count DESC, value ASC
+		LIMIT $` + fmt.Sprint(len(q.args)+1) + `;`
+
+	rows, err := r.db.QueryContext(ctx, query, append(q.args, size)...)
+	if err != nil {
+		return nil, fmt.Errorf("arama faseti hesaplanırken hata: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.FacetCount{}
+	for rows.Next() {
+		var fc domain.FacetCount
+		if err := rows.Scan(&fc.Value, &fc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, fc)
+	}
+	return counts, rows.Err()
+}
+
+// backfillSearchVectors fills search_vector for rows stored before the trigger
+// existed, one small batch per statement so messages is never locked for long.
+// Those rows are missing from search results until their batch is done.
+func (r *Repository) backfillSearchVectors(batchSize int, pause time.Duration) {
+	total := int64(0)
+	for {
+		result, err := r.db.Exec(`
+			UPDATE messages
+			SET search_vector = to_tsvector('turkish', coalesce(content, '')) || to_tsvector('english', coalesce(content, ''))
+			WHERE id IN (
+				SELECT id FROM messages WHERE search_vector IS NULL LIMIT $1 FOR UPDATE SKIP LOCKED
+			);`, batchSize)
+		if err != nil {
+			log.Printf("Arama vektörleri doldurulurken hata: %v", err)
+			return
+		}
+		n, _ := result.RowsAffected()
+		total += n
+		if n < int64(batchSize) {
+			break
+		}
+		time.Sleep(pause)
+	}
+	if total > 0 {
+		log.Printf("%d mesajın arama vektörü dolduruldu", total)
+	}
+}
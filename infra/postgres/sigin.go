@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"kick-chat/domain"
+	"log"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -45,8 +46,7 @@ func (r *Repository) SignIn(ctx context.Context, identifier, password string) (*
 		// Başarısız giriş denemesini kaydet
 		_, err := tx.ExecContext(ctx, "UPDATE users SET failed_login_attempts = $1 WHERE id = $2", failedAttempts+1, auth.ID)
 		if err != nil {
-			// Loglama mekanizması burada kullanılabilir
-			fmt.Errorf("Failed to update login attempts: %v\n", err)
+			log.Printf("Failed to update login attempts: %v", err)
 		}
 		return nil, ErrInvalidCredentials
 	}
@@ -54,8 +54,7 @@ func (r *Repository) SignIn(ctx context.Context, identifier, password string) (*
 	// Başarılı giriş, deneme sayacını sıfırla ve son giriş zamanını güncelle
 	_, err = tx.ExecContext(ctx, "UPDATE users SET failed_login_attempts = 0, last_login = NOW() WHERE id = $1", auth.ID)
 	if err != nil {
-		// Loglama mekanizması burada kullanılabilir
-		fmt.Errorf("Failed to update last login: %v\n", err)
+		log.Printf("Failed to update last login: %v", err)
 	}
 
 	return &auth, tx.Commit()
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+)
+
+// GetStreamerChannel returns the last channel metadata resolved for username
+func (r *Repository) GetStreamerChannel(ctx context.Context, username string) (*domain.ChannelInfo, error) {
+	query := `
+		SELECT username, chatroom_id, COALESCE(kick_user_id, 0), COALESCE(profile_pic, ''),
+			COALESCE(metadata_updated_at, updated_at)
+		FROM streamers
+		WHERE LOWER(username) = LOWER($1) AND chatroom_id IS NOT NULL;`
+
+	var info domain.ChannelInfo
+	err := r.db.QueryRowContext(ctx, query, username).Scan(
+		&info.Username, &info.ChatroomID, &info.KickUserID, &info.ProfilePic, &info.ResolvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("yayıncı kanal bilgisi okunamadı: %w", err)
+	}
+	return &info, nil
+}
+
+// UpsertStreamerChannel stores freshly resolved metadata so it survives provider outages
+func (r *Repository) UpsertStreamerChannel(ctx context.Context, info domain.ChannelInfo) error {
+	query := `
+		INSERT INTO streamers (username, kick_user_id, profile_pic, chatroom_id, metadata_updated_at)
+		VALUES ($1, NULLIF($2, 0), NULLIF($3, ''), $4, NOW())
+		ON CONFLICT (username) DO UPDATE SET
+			kick_user_id = COALESCE(EXCLUDED.kick_user_id, streamers.kick_user_id),
+			profile_pic = COALESCE(EXCLUDED.profile_pic, streamers.profile_pic),
+			chatroom_id = EXCLUDED.chatroom_id,
+			metadata_updated_at = NOW(),
+			updated_at = NOW();`
+
+	if _, err := r.db.ExecContext(ctx, query, info.Username, info.KickUserID, info.ProfilePic, info.ChatroomID); err != nil {
+		return fmt.Errorf("yayıncı kanal bilgisi kaydedilemedi: %w", err)
+	}
+	return nil
+}
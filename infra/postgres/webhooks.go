@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const webhookEndpointColumns = `id, user_id, url, format, events, streamer, rate_limit_per_minute, enabled, created_at`
+
+func scanWebhookEndpoint(row rowScanner, extra ...any) (domain.WebhookEndpoint, error) {
+	var e domain.WebhookEndpoint
+	var streamer sql.NullString
+	dest := []any{&e.ID, &e.UserID, &e.URL, &e.Format, pq.Array(&e.Events), &streamer, &e.RateLimitPerMin, &e.Enabled, &e.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if streamer.Valid {
+		e.Streamer = &streamer.String
+	}
+	return e, err
+}
+
+func (r *Repository) CreateWebhookEndpoint(ctx context.Context, endpoint domain.WebhookEndpoint) (*domain.WebhookEndpoint, error) {
+	query := `
+		INSERT INTO webhook_endpoints (user_id, url, format, secret, events, streamer, rate_limit_per_minute)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + webhookEndpointColumns + `;`
+
+	created, err := scanWebhookEndpoint(r.db.QueryRowContext(ctx, query, endpoint.UserID, endpoint.URL, endpoint.Format, endpoint.Secret,
+		pq.Array(endpoint.Events), endpoint.Streamer, endpoint.RateLimitPerMin))
+	if err != nil {
+		return nil, fmt.Errorf("webhook oluşturulurken hata: %w", err)
+	}
+	created.Secret = endpoint.Secret
+	return &created, nil
+}
+
+func (r *Repository) CountWebhookEndpoints(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_endpoints WHERE user_id = $1;`, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("webhooklar sayılırken hata: %w", err)
+	}
+	return count, nil
+}
+
+func (r *Repository) GetUserWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	return r.queryWebhookEndpoints(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE user_id = $1 ORDER BY created_at;`, userID)
+}
+
+// GetEnabledWebhookEndpoints loads every endpoint the dispatcher routes events to
+func (r *Repository) GetEnabledWebhookEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	return r.queryWebhookEndpoints(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE enabled = true;`)
+}
+
+func (r *Repository) queryWebhookEndpoints(ctx context.Context, query string, args ...any) ([]domain.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("webhooklar getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var endpoints []domain.WebhookEndpoint
+	for rows.Next() {
+		e, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("webhook satırı okunurken hata: %w", err)
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+func (r *Repository) DeleteWebhookEndpoint(ctx context.Context, userID, endpointID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1 AND user_id = $2;`, endpointID, userID)
+	if err != nil {
+		return fmt.Errorf("webhook silinirken hata: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// OwnsWebhookEndpoint returns ErrNotFound for unknown endpoints and false for other users' ones
+func (r *Repository) OwnsWebhookEndpoint(ctx context.Context, userID, endpointID uuid.UUID) (bool, error) {
+	var owner uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT user_id FROM webhook_endpoints WHERE id = $1;`, endpointID).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, domain.ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("webhook okunurken hata: %w", err)
+	}
+	return owner == userID, nil
+}
+
+func (r *Repository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, next_attempt_at) VALUES `)
+	args := make([]any, 0, len(deliveries)*5)
+	for i, d := range deliveries {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		n := len(args)
+		// A nil NextAttemptAt is due right away, a later one lets a burst collect first
+		fmt.Fprintf(&sb, "($%d, $%d, $%d, $%d, COALESCE($%d, NOW()))", n+1, n+2, n+3, n+4, n+5)
+		args = append(args, d.EndpointID, d.EventID, d.EventType, string(d.Payload), d.NextAttemptAt)
+	}
+
+	if _, err := r.db.ExecContext(ctx, sb.String(), args...); err != nil {
+		return r.wrapMessageError("webhook kuyruğuna yazılırken hata", err)
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries takes up to limit due deliveries and hides them for lease,
+// so a worker that dies mid-send only delays them. SKIP LOCKED spreads rows over replicas.
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhook_endpoints e
+		WHERE d.id = due.id AND e.id = d.endpoint_id
+		RETURNING d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at,
+			e.url, e.format, e.secret, e.rate_limit_per_minute;`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("webhook kuyruğu okunurken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		d := domain.WebhookDelivery{Status: domain.WebhookStatusPending}
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Attempts, &d.CreatedAt,
+			&d.URL, &d.Format, &d.Secret, &d.RateLimitPerMin); err != nil {
+			return nil, fmt.Errorf("webhook teslimatı okunurken hata: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *Repository) MarkWebhookDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, last_status_code = $2, last_error = NULL,
+			delivered_at = NOW(), next_attempt_at = NULL
+		WHERE id = $1;`, deliveryID, statusCode)
+	if err != nil {
+		return fmt.Errorf("webhook teslimatı güncellenirken hata: %w", err)
+	}
+	return nil
+}
+
+// MarkWebhookFailed records a failed attempt; a nil nextAttempt dead-letters the delivery
+func (r *Repository) MarkWebhookFailed(ctx context.Context, deliveryID uuid.UUID, statusCode *int, lastError string, nextAttempt *time.Time) error {
+	status := domain.WebhookStatusPending
+	if nextAttempt == nil {
+		status = domain.WebhookStatusDead
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4, next_attempt_at = $5
+		WHERE id = $1;`, deliveryID, status, statusCode, lastError, nextAttempt)
+	if err != nil {
+		return fmt.Errorf("webhook teslimatı güncellenirken hata: %w", err)
+	}
+	return nil
+}
+
+// DeferWebhookDelivery pushes a delivery back without counting an attempt, used by rate limiting
+func (r *Repository) DeferWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id = $1;`, deliveryID, at); err != nil {
+		return fmt.Errorf("webhook teslimatı ertelenirken hata: %w", err)
+	}
+	return nil
+}
+
+// RequeueWebhookDelivery puts a dead-lettered delivery back in the queue with a fresh attempt count
+func (r *Repository) RequeueWebhookDelivery(ctx context.Context, endpointID, deliveryID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND endpoint_id = $2 AND status = 'dead';`, deliveryID, endpointID)
+	if err != nil {
+		return fmt.Errorf("webhook teslimatı kuyruğa alınırken hata: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// GetWebhookDeliveries pages through an endpoint's delivery log, keyset on (created_at, id)
+func (r *Repository) GetWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	conditions := []string{"endpoint_id = $1"}
+	args := []any{filter.EndpointID}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Before != nil {
+		args = append(args, filter.Before.Timestamp, filter.Before.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	query := `
+		SELECT id, endpoint_id, event_id, event_type, status, attempts, next_attempt_at, last_status_code,
+			COALESCE(last_error, ''), created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $` + fmt.Sprint(len(args)) + `;`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("webhook teslimatları getirilirken hata: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var statusCode sql.NullInt32
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&statusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, fmt.Errorf("webhook teslimatı okunurken hata: %w", err)
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int32)
+			d.LastStatusCode = &code
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,115 @@
+// Package rabbitmq publishes chat events to an AMQP topic exchange
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Publisher holds one confirm-mode channel and reconnects lazily: a publish on a
+// dead connection fails, drops it, and the next publish dials again.
+type Publisher struct {
+	url      string
+	exchange string
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+func NewPublisher(url, exchange string) *Publisher {
+	return &Publisher{url: url, exchange: exchange}
+}
+
+func (p *Publisher) connectLocked() error {
+	if p.ch != nil && !p.ch.IsClosed() {
+		return nil
+	}
+	p.closeLocked()
+
+	conn, err := amqp.DialConfig(p.url, amqp.Config{
+		Heartbeat: 10 * time.Second,
+		Dial:      amqp.DefaultDial(5 * time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("rabbitmq bağlantısı kurulamadı: %w", err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("rabbitmq kanalı açılamadı: %w", err)
+	}
+	if err := ch.ExchangeDeclare(p.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("exchange tanımlanamadı: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return fmt.Errorf("publisher confirm açılamadı: %w", err)
+	}
+
+	p.conn, p.ch = conn, ch
+	return nil
+}
+
+func (p *Publisher) closeLocked() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn, p.ch = nil, nil
+}
+
+// PublishBatch sends every event and returns once the broker answered all of them.
+// Events the broker nacked are listed in a *domain.RejectedEventsError, the rest
+// were confirmed; any other error means the whole batch must be retried.
+func (p *Publisher) PublishBatch(ctx context.Context, events []domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.connectLocked(); err != nil {
+		return err
+	}
+
+	confirms := make([]*amqp.DeferredConfirmation, 0, len(events))
+	for _, e := range events {
+		confirm, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, p.exchange, e.RoutingKey, false, false, amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+			Body:         e.Payload,
+		})
+		if err != nil {
+			p.closeLocked()
+			return fmt.Errorf("rabbitmq'ya yayınlanamadı: %w", err)
+		}
+		confirms = append(confirms, confirm)
+	}
+
+	var nacked []int
+	for i, confirm := range confirms {
+		acked, err := confirm.WaitContext(ctx)
+		if err != nil {
+			p.closeLocked()
+			return fmt.Errorf("rabbitmq onayı alınamadı: %w", err)
+		}
+		if !acked {
+			nacked = append(nacked, i)
+		}
+	}
+	if len(nacked) > 0 {
+		return &domain.RejectedEventsError{Indexes: nacked}
+	}
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeLocked()
+	return nil
+}
@@ -34,7 +34,7 @@ func (a *App) initDependencies() {
 	a.postgresRepo = InitDatabase(a.config)
 
 	// HTTP handler'larını hazırla
-	a.httpHandlers = SetupHTTPHandlers(a.postgresRepo, a.sessionManager)
+	a.httpHandlers = SetupHTTPHandlers(a.config, a.postgresRepo, a.sessionManager)
 
 	// HTTP sunucusu kurulumu
 	a.fiberApp = SetupServer(a.config, a.httpHandlers, a.sessionManager)
@@ -68,6 +68,10 @@ func (a *App) Start() error {
 	case <-time.After(100 * time.Millisecond): // Sunucunun bind etmesi için kısa bekleme
 		log.Println("Server started on port:", a.config.Server.Port)
 		graceful.WaitForShutdown(a.fiberApp, 5*time.Second, context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		a.httpHandlers.Shutdown(ctx)
 		return nil
 	}
 }
@@ -12,8 +12,6 @@ import (
 )
 
 type PostgresRepository interface {
-	InsertListener(ctx context.Context, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, newIsActive bool, newEndTime *time.Time, newDuration int) (uuid.UUID, error)
-
 	InsertUserListenerRequest(listenerID uuid.UUID, userID uuid.UUID, requestTime time.Time, endTime time.Time) error
 
 	GetStreamerByUsername(ctx context.Context, username string) (*struct {
@@ -40,9 +38,15 @@ type PostgresRepository interface {
 	}, error)
 	// GÜNCELLENDİ: InsertMessage fonksiyonu link bilgilerini de alıyor
 	InsertMessage(listenerID uuid.UUID, senderUsername, content string, timestamp time.Time, hasLink bool, extractedLinks []string) error
+	InsertMessages(ctx context.Context, messages []domain.ChatMessage) error
+	InsertChatEvent(listenerID uuid.UUID, eventType, kickEventID string, payload []byte, occurredAt time.Time) error
+	InsertConnectionAttempts(ctx context.Context, attempts []domain.ConnectionAttempt) error
 	// YENİ: Eklenen fonksiyonlar
 	UpdateListenerStatus(listenerID uuid.UUID, isActive bool) error
 	UpdateListenerEndTime(ctx context.Context, listenerID uuid.UUID, endTime time.Time) error
+	UpdateListenerState(ctx context.Context, listenerIDs []uuid.UUID, state string, changedAt time.Time, lastError string) error
+	GetDesiredListeners(ctx context.Context) ([]domain.DesiredListener, error)
+	DeactivateUnrequestedListeners(ctx context.Context) (int64, error)
 	GetMessagesByListener(listenerID uuid.UUID, limit, offset int) ([]struct {
 		ID               uuid.UUID
 		SenderUsername   string
@@ -52,6 +56,46 @@ type PostgresRepository interface {
 		ExtractedLinks   []string
 	}, error)
 
+	GetMessagesAfterKickID(ctx context.Context, streamers []string, kickMessageID string, limit int) ([]domain.StoredMessage, error)
+	GetUserStreamers(ctx context.Context, userID uuid.UUID) ([]string, error)
+	CanReadListener(ctx context.Context, userID, listenerID uuid.UUID) (bool, error)
+	CanReadStreamer(ctx context.Context, userID uuid.UUID, streamer string) (bool, error)
+	StreamMessages(ctx context.Context, filter domain.ExportFilter, fn func(domain.StoredMessage) error) error
+	GetMessages(ctx context.Context, filter domain.MessageFilter) ([]domain.StoredMessage, error)
+	SearchMessages(ctx context.Context, filter domain.SearchFilter) (*domain.SearchResult, error)
+	GetStreamerChannel(ctx context.Context, username string) (*domain.ChannelInfo, error)
+	UpsertStreamerChannel(ctx context.Context, info domain.ChannelInfo) error
+	OpenListenRequest(ctx context.Context, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, requestTime, endTime time.Time, quota time.Duration) (uuid.UUID, error)
+	SaveListenRequest(ctx context.Context, listenerID, userID uuid.UUID, requestTime, endTime time.Time, quota time.Duration) (uuid.UUID, error)
+	GetUserActiveRequest(ctx context.Context, userID uuid.UUID, streamer string) (*domain.ListenRequest, error)
+	EndListenRequest(ctx context.Context, listenerID, userID uuid.UUID) error
+	GetUserListeners(ctx context.Context, userID uuid.UUID) ([]domain.UserListener, error)
+	SetListenerSinks(ctx context.Context, listenerID uuid.UUID, sinks []string) error
+	CreateAlertRule(ctx context.Context, rule domain.AlertRule) (*domain.AlertRule, error)
+	CountAlertRules(ctx context.Context, userID uuid.UUID) (int, error)
+	GetUserAlertRules(ctx context.Context, userID uuid.UUID) ([]domain.AlertRule, error)
+	GetEnabledAlertRules(ctx context.Context) ([]domain.AlertRule, error)
+	SetAlertRuleEnabled(ctx context.Context, userID, ruleID uuid.UUID, enabled bool) (*domain.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, userID, ruleID uuid.UUID) error
+	InsertAlert(ctx context.Context, alert domain.Alert) (*domain.Alert, error)
+	GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.Alert, error)
+	CreateWebhookEndpoint(ctx context.Context, endpoint domain.WebhookEndpoint) (*domain.WebhookEndpoint, error)
+	CountWebhookEndpoints(ctx context.Context, userID uuid.UUID) (int, error)
+	GetUserWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]domain.WebhookEndpoint, error)
+	GetEnabledWebhookEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, userID, endpointID uuid.UUID) error
+	OwnsWebhookEndpoint(ctx context.Context, userID, endpointID uuid.UUID) (bool, error)
+	EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	MarkWebhookDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int) error
+	MarkWebhookFailed(ctx context.Context, deliveryID uuid.UUID, statusCode *int, lastError string, nextAttempt *time.Time) error
+	DeferWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, at time.Time) error
+	RequeueWebhookDelivery(ctx context.Context, endpointID, deliveryID uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+	InsertOutboxEvents(ctx context.Context, events []domain.OutboxEvent) error
+	DrainOutbox(ctx context.Context, limit, maxAttempts int, publish func([]domain.OutboxEvent) error) (int, int, error)
+	HasOutboxEvents(ctx context.Context) (bool, error)
+
 	SignUp(ctx context.Context, auth *domain.User) (uuid.UUID, error)
 	SignIn(ctx context.Context, identifier, password string) (*domain.User, error)
 }
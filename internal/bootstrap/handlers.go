@@ -1,11 +1,18 @@
 package bootstrap
 
 import (
+	"context"
+	"fmt"
+	"kick-chat/infra/cache"
+	"kick-chat/infra/cluster"
+	"kick-chat/infra/rabbitmq"
+	"kick-chat/internal/config"
 	authHandlers "kick-chat/internal/handlers/auth"
 	chatHandlers "kick-chat/internal/handlers/chat"
 	authUsecase "kick-chat/internal/usecases/auth"
 	chatUsecase "kick-chat/internal/usecases/chat"
 	"log"
+	"os"
 )
 
 // func SetupHTTPHandlers() map[string]interface{} {
@@ -17,23 +24,166 @@ import (
 //		}
 //	}
 type Handlers struct {
-	Hello  *chatHandlers.HelloHandler
-	Listen *chatHandlers.ListenHandler
-	Signup *authHandlers.SignUpHandler
-	Signin *authHandlers.SignInHandler
+	Hello    *chatHandlers.HelloHandler
+	Listen   *chatHandlers.ListenHandler
+	Extend   *chatHandlers.ListenAdjustHandler
+	Shorten  *chatHandlers.ListenAdjustHandler
+	Unlisten *chatHandlers.UnsubscribeHandler
+	Mine     *chatHandlers.MyListenersHandler
+	LiveWS   *chatHandlers.LiveWSHandler
+	LiveSSE  *chatHandlers.LiveSSEHandler
+	Messages *chatHandlers.MessagesHandler
+	Search   *chatHandlers.SearchHandler
+	Export   *chatHandlers.ExportHandler
+	// Alarm kuralları ve alarmlar
+	CreateAlertRule *chatHandlers.CreateAlertRuleHandler
+	AlertRules      *chatHandlers.AlertRulesHandler
+	UpdateAlertRule *chatHandlers.UpdateAlertRuleHandler
+	DeleteAlertRule *chatHandlers.DeleteAlertRuleHandler
+	Alerts          *chatHandlers.AlertsHandler
+	// Kullanıcı webhookları ve teslimat günlüğü
+	CreateWebhook     *chatHandlers.CreateWebhookHandler
+	Webhooks          *chatHandlers.WebhooksHandler
+	DeleteWebhook     *chatHandlers.DeleteWebhookHandler
+	WebhookDeliveries *chatHandlers.WebhookDeliveriesHandler
+	RedeliverWebhook  *chatHandlers.RedeliverWebhookHandler
+	Signup            *authHandlers.SignUpHandler
+	Signin            *authHandlers.SignInHandler
 	// Diğer handler'lar
+
+	// Shutdown stops the listeners, hands their streamers to the other replicas
+	// and saves whatever events are still buffered
+	Shutdown func(ctx context.Context)
 }
 
-func SetupHTTPHandlers(postgresRepo PostgresRepository, sessionManager SessionManager) *Handlers {
-	listenUseCase := chatUsecase.NewListenUseCase(postgresRepo)
-	if err := listenUseCase.StartActiveListenersOnStartup(); err != nil {
+func SetupHTTPHandlers(cfg *config.Config, postgresRepo PostgresRepository, sessionManager SessionManager) *Handlers {
+	instanceID := newInstanceID(cfg)
+	liveHub := chatUsecase.NewLiveHub(chatUsecase.AppConfig)
+	go liveHub.Run(context.Background())
+	liveFanout := chatUsecase.NewLiveFanout(liveHub, newEventBus(cfg, sessionManager), instanceID, chatUsecase.AppConfig)
+	go liveFanout.Run(context.Background())
+	channelResolver := chatUsecase.NewDefaultChannelResolver(chatUsecase.AppConfig, cache.NewChannelCache(sessionManager.GetRedisClient()), postgresRepo)
+	exporter, stopExporter := newBusExporter(cfg, postgresRepo)
+	alertEngine := chatUsecase.NewAlertEngine(postgresRepo, exporter, chatUsecase.AppConfig)
+	go alertEngine.Run(context.Background())
+	alertUseCase := chatUsecase.NewAlertUseCase(postgresRepo, alertEngine)
+	webhookDispatcher := chatUsecase.NewWebhookDispatcher(postgresRepo, chatUsecase.AppConfig)
+	alertEngine.OnAlert(webhookDispatcher.OnAlert)
+	go webhookDispatcher.Run(context.Background())
+	webhookUseCase := chatUsecase.NewWebhookUseCase(postgresRepo, webhookDispatcher)
+	listenUseCase, err := chatUsecase.NewListenUseCase(postgresRepo, liveFanout, channelResolver,
+		newListenerCoordinator(cfg, sessionManager, instanceID), exporter, alertEngine, webhookDispatcher, sinkSpecs(cfg, exporter != nil))
+	if err != nil {
+		log.Fatalf("Çıktı yapılandırması geçersiz: %v", err)
+	}
+	liveFeedUseCase := chatUsecase.NewLiveFeedUseCase(liveHub, liveFanout, postgresRepo)
+	if err := listenUseCase.StartReconciler(context.Background()); err != nil {
 		log.Printf("Aktif dinleyicileri başlatırken hata: %v", err)
 		// Hata kritik değilse fatal olmayabilir, loglayıp devam edebiliriz.
 	}
 	return &Handlers{
-		Hello:  chatHandlers.NewHelloHandler(chatUsecase.NewhelloUseCase(postgresRepo, "naber")),
-		Listen: chatHandlers.NewListenHandler(listenUseCase),
-		Signup: authHandlers.NewSignUpHandler(authUsecase.NewSignUpUseCase(postgresRepo)),
-		Signin: authHandlers.NewSignInHandler(authUsecase.NewSignInUseCase(postgresRepo, sessionManager)),
+		Hello:    chatHandlers.NewHelloHandler(chatUsecase.NewhelloUseCase(postgresRepo, "naber")),
+		Listen:   chatHandlers.NewListenHandler(listenUseCase),
+		Extend:   chatHandlers.NewListenExtendHandler(listenUseCase),
+		Shorten:  chatHandlers.NewListenShortenHandler(listenUseCase),
+		Unlisten: chatHandlers.NewUnsubscribeHandler(listenUseCase),
+		Mine:     chatHandlers.NewMyListenersHandler(chatUsecase.NewMyListenersUseCase(postgresRepo)),
+		LiveWS:   chatHandlers.NewLiveWSHandler(liveFeedUseCase),
+		LiveSSE:  chatHandlers.NewLiveSSEHandler(liveFeedUseCase),
+		Messages: chatHandlers.NewMessagesHandler(chatUsecase.NewMessageHistoryUseCase(postgresRepo)),
+		Search:   chatHandlers.NewSearchHandler(chatUsecase.NewMessageSearchUseCase(postgresRepo)),
+		Export:   chatHandlers.NewExportHandler(chatUsecase.NewMessageExportUseCase(postgresRepo)),
+		Signup:   authHandlers.NewSignUpHandler(authUsecase.NewSignUpUseCase(postgresRepo)),
+		Signin:   authHandlers.NewSignInHandler(authUsecase.NewSignInUseCase(postgresRepo, sessionManager)),
+		// The listeners' sinks feed the exporter, it parks what is left once they are drained
+		Shutdown: func(ctx context.Context) {
+			listenUseCase.Shutdown(ctx)
+			stopExporter(ctx)
+		},
+
+		CreateAlertRule: chatHandlers.NewCreateAlertRuleHandler(alertUseCase),
+		AlertRules:      chatHandlers.NewAlertRulesHandler(alertUseCase),
+		UpdateAlertRule: chatHandlers.NewUpdateAlertRuleHandler(alertUseCase),
+		DeleteAlertRule: chatHandlers.NewDeleteAlertRuleHandler(alertUseCase),
+		Alerts:          chatHandlers.NewAlertsHandler(alertUseCase),
+
+		CreateWebhook:     chatHandlers.NewCreateWebhookHandler(webhookUseCase),
+		Webhooks:          chatHandlers.NewWebhooksHandler(webhookUseCase),
+		DeleteWebhook:     chatHandlers.NewDeleteWebhookHandler(webhookUseCase),
+		WebhookDeliveries: chatHandlers.NewWebhookDeliveriesHandler(webhookUseCase),
+		RedeliverWebhook:  chatHandlers.NewRedeliverWebhookHandler(webhookUseCase),
+	}
+}
+
+// newListenerCoordinator returns nil when clustering is off, the listen use case then
+// owns every streamer itself
+func newListenerCoordinator(cfg *config.Config, sessionManager SessionManager, instanceID string) chatUsecase.ListenerCoordinator {
+	if !cfg.Cluster.Enabled {
+		return nil
+	}
+	log.Printf("Küme modu açık, sunucu kimliği: %s", instanceID)
+	return cluster.NewRedisCoordinator(sessionManager.GetRedisClient(), instanceID,
+		chatUsecase.AppConfig.ClusterLeaseTTL, chatUsecase.AppConfig.ClusterInstanceTTL)
+}
+
+// newEventBus shares live events between replicas, nil keeps them in process
+func newEventBus(cfg *config.Config, sessionManager SessionManager) chatUsecase.EventBus {
+	if !cfg.Cluster.Enabled {
+		return nil
+	}
+	return cluster.NewRedisEventBus(sessionManager.GetRedisClient(),
+		chatUsecase.AppConfig.LiveReplaySize, chatUsecase.AppConfig.LiveReplayTTL)
+}
+
+// newBusExporter returns nil when RabbitMQ is disabled. stop parks the exporter's
+// queue in the outbox and waits up to ctx for it.
+func newBusExporter(cfg *config.Config, postgresRepo PostgresRepository) (exporter *chatUsecase.BusExporter, stop func(ctx context.Context)) {
+	if !cfg.RabbitMQ.Enabled {
+		return nil, func(context.Context) {}
+	}
+	log.Printf("Olaylar RabbitMQ'ya gönderilecek, exchange: %s", cfg.RabbitMQ.Exchange)
+	exporter = chatUsecase.NewBusExporter(rabbitmq.NewPublisher(cfg.RabbitMQ.URL, cfg.RabbitMQ.Exchange), postgresRepo, chatUsecase.AppConfig)
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exporter.Run(runCtx)
+	}()
+	return exporter, func(ctx context.Context) {
+		cancel()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			log.Printf("Bus kuyruğu outbox'a alınamadan çıkılıyor: %v", ctx.Err())
+		}
+	}
+}
+
+func sinkSpecs(cfg *config.Config, withBus bool) []chatUsecase.SinkSpec {
+	if len(cfg.Sinks) == 0 {
+		return chatUsecase.DefaultSinkSpecs(withBus)
+	}
+	specs := make([]chatUsecase.SinkSpec, 0, len(cfg.Sinks))
+	for _, sink := range cfg.Sinks {
+		events, err := chatUsecase.ParseSinkEvents(sink.Events)
+		if err != nil {
+			log.Fatalf("'%s' çıktısı geçersiz: %v", sink.Type, err)
+		}
+		specs = append(specs, chatUsecase.SinkSpec{
+			Name:     sink.Name,
+			Type:     sink.Type,
+			Events:   events,
+			Settings: sink.Settings,
+		})
+	}
+	return specs
+}
+
+func newInstanceID(cfg *config.Config) string {
+	if cfg.Cluster.InstanceID != "" {
+		return cfg.Cluster.InstanceID
 	}
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
 }
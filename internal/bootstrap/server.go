@@ -25,8 +25,27 @@ func SetupServer(config *config.Config, httpHandlers *Handlers, sessionManager S
 
 	helloHandler := httpHandlers.Hello
 	listenHandler := httpHandlers.Listen
+	extendHandler := httpHandlers.Extend
+	shortenHandler := httpHandlers.Shorten
+	unlistenHandler := httpHandlers.Unlisten
+	myListenersHandler := httpHandlers.Mine
 	signupHandler := httpHandlers.Signup
 	signinHandler := httpHandlers.Signin
+	liveWSHandler := httpHandlers.LiveWS
+	liveSSEHandler := httpHandlers.LiveSSE
+	messagesHandler := httpHandlers.Messages
+	searchHandler := httpHandlers.Search
+	exportHandler := httpHandlers.Export
+	createAlertRuleHandler := httpHandlers.CreateAlertRule
+	alertRulesHandler := httpHandlers.AlertRules
+	updateAlertRuleHandler := httpHandlers.UpdateAlertRule
+	deleteAlertRuleHandler := httpHandlers.DeleteAlertRule
+	alertsHandler := httpHandlers.Alerts
+	createWebhookHandler := httpHandlers.CreateWebhook
+	webhooksHandler := httpHandlers.Webhooks
+	deleteWebhookHandler := httpHandlers.DeleteWebhook
+	webhookDeliveriesHandler := httpHandlers.WebhookDeliveries
+	redeliverWebhookHandler := httpHandlers.RedeliverWebhook
 	authMiddleware := middleware.NewAuthMiddleware(sessionManager)
 	app.Get("/hello/:name", handler.HandleBasic[chatHandlers.HelloRequest, chatHandlers.HelloResponse](helloHandler))
 	app.Post("/signup", handler.HandleBasic[authHandlers.SignUpRequest, authHandlers.SignUpResponse](signupHandler))
@@ -35,6 +54,26 @@ func SetupServer(config *config.Config, httpHandlers *Handlers, sessionManager S
 	protected := app.Group("/", authMiddleware.Authenticate())
 	{
 		protected.Post("/listen/:username", handler.HandleWithFiber[chatHandlers.ListenRequest, chatHandlers.ListenResponse](listenHandler))
+		protected.Post("/listen/:username/extend", handler.HandleWithFiber[chatHandlers.ListenAdjustRequest, chatHandlers.ListenAdjustResponse](extendHandler))
+		protected.Post("/listen/:username/shorten", handler.HandleWithFiber[chatHandlers.ListenAdjustRequest, chatHandlers.ListenAdjustResponse](shortenHandler))
+		protected.Delete("/listen/:username", handler.HandleWithFiber[chatHandlers.UnsubscribeRequest, chatHandlers.UnsubscribeResponse](unlistenHandler))
+		protected.Get("/me/listeners", handler.HandleWithFiber[chatHandlers.MyListenersRequest, chatHandlers.MyListenersResponse](myListenersHandler))
+		protected.Get("/ws/listen/:username", liveWSHandler.Upgrade, liveWSHandler.Handle())
+		protected.Get("/sse/listen", liveSSEHandler.Combined)
+		protected.Get("/sse/listen/:username", liveSSEHandler.Streamer)
+		protected.Get("/listeners/:id/messages", handler.HandleWithFiber[chatHandlers.MessagesRequest, chatHandlers.MessagesResponse](messagesHandler))
+		protected.Get("/search/messages", handler.HandleWithFiber[chatHandlers.SearchRequest, chatHandlers.SearchResponse](searchHandler))
+		protected.Get("/export/messages", handler.HandleStream[chatHandlers.ExportRequest](exportHandler))
+		protected.Post("/alerts/rules", handler.HandleWithFiber[chatHandlers.CreateAlertRuleRequest, chatHandlers.AlertRuleResponse](createAlertRuleHandler))
+		protected.Get("/alerts/rules", handler.HandleWithFiber[chatHandlers.AlertRulesRequest, chatHandlers.AlertRulesResponse](alertRulesHandler))
+		protected.Patch("/alerts/rules/:id", handler.HandleWithFiber[chatHandlers.UpdateAlertRuleRequest, chatHandlers.AlertRuleResponse](updateAlertRuleHandler))
+		protected.Delete("/alerts/rules/:id", handler.HandleWithFiber[chatHandlers.DeleteAlertRuleRequest, chatHandlers.DeleteAlertRuleResponse](deleteAlertRuleHandler))
+		protected.Get("/alerts", handler.HandleWithFiber[chatHandlers.AlertsRequest, chatHandlers.AlertsResponse](alertsHandler))
+		protected.Post("/webhooks", handler.HandleWithFiber[chatHandlers.CreateWebhookRequest, chatHandlers.WebhookResponse](createWebhookHandler))
+		protected.Get("/webhooks", handler.HandleWithFiber[chatHandlers.WebhooksRequest, chatHandlers.WebhooksResponse](webhooksHandler))
+		protected.Delete("/webhooks/:id", handler.HandleWithFiber[chatHandlers.DeleteWebhookRequest, chatHandlers.WebhookMessageResponse](deleteWebhookHandler))
+		protected.Get("/webhooks/:id/deliveries", handler.HandleWithFiber[chatHandlers.WebhookDeliveriesRequest, chatHandlers.WebhookDeliveriesResponse](webhookDeliveriesHandler))
+		protected.Post("/webhooks/:id/deliveries/:delivery_id/retry", handler.HandleWithFiber[chatHandlers.RedeliverWebhookRequest, chatHandlers.WebhookMessageResponse](redeliverWebhookHandler))
 	}
 
 	return app
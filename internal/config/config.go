@@ -11,6 +11,9 @@ type Config struct {
 	Server       ServerConfig       `mapstructure:"server"`
 	Postgres     PostgresConfig     `mapstructure:"postgres"`
 	SessionRedis SessionRedisConfig `mapstructure:"sessionredis"`
+	Cluster      ClusterConfig      `mapstructure:"cluster"`
+	RabbitMQ     RabbitMQConfig     `mapstructure:"rabbitmq"`
+	Sinks        []SinkConfig       `mapstructure:"sinks"`
 }
 
 type AppConfig struct {
@@ -37,6 +40,29 @@ type SessionRedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// ClusterConfig lets replicas share the listeners through the session Redis.
+// InstanceID defaults to hostname-pid when empty.
+type ClusterConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	InstanceID string `mapstructure:"instance_id"`
+}
+
+// RabbitMQConfig enables publishing chat events to a topic exchange
+type RabbitMQConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	URL      string `mapstructure:"url"`
+	Exchange string `mapstructure:"exchange"`
+}
+
+// SinkConfig is one output for chat events: console, postgres, webhook, bus or file.
+// Events optionally limits it to some event types, e.g. [message].
+type SinkConfig struct {
+	Name     string            `mapstructure:"name"`
+	Type     string            `mapstructure:"type"`
+	Events   []string          `mapstructure:"events"`
+	Settings map[string]string `mapstructure:"settings"`
+}
+
 func Read() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AlertRuleResponse = domain.AlertRule
+
+type CreateAlertRuleRequest struct {
+	// Streamer scopes the rule to one channel, empty means every channel the user listens to
+	Streamer        string `json:"streamer"`
+	Kind            string `json:"kind" binding:"required"`
+	Pattern         string `json:"pattern" binding:"required"`
+	CooldownSeconds *int   `json:"cooldown_seconds"`
+}
+
+type CreateAlertRuleHandler struct {
+	usecase usecase.AlertUseCase
+}
+
+func NewCreateAlertRuleHandler(usecase usecase.AlertUseCase) *CreateAlertRuleHandler {
+	return &CreateAlertRuleHandler{usecase: usecase}
+}
+
+func (h *CreateAlertRuleHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *CreateAlertRuleRequest) (*AlertRuleResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	return h.usecase.CreateRule(ctx, userData.UserID, usecase.AlertRuleInput{
+		Streamer:        req.Streamer,
+		Kind:            req.Kind,
+		Pattern:         req.Pattern,
+		CooldownSeconds: req.CooldownSeconds,
+	})
+}
+
+type AlertRulesRequest struct{}
+
+type AlertRulesResponse struct {
+	Rules []domain.AlertRule `json:"rules"`
+}
+
+type AlertRulesHandler struct {
+	usecase usecase.AlertUseCase
+}
+
+func NewAlertRulesHandler(usecase usecase.AlertUseCase) *AlertRulesHandler {
+	return &AlertRulesHandler{usecase: usecase}
+}
+
+func (h *AlertRulesHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *AlertRulesRequest) (*AlertRulesResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	rules, err := h.usecase.ListRules(ctx, userData.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &AlertRulesResponse{Rules: rules}, nil
+}
+
+type UpdateAlertRuleRequest struct {
+	ID      string `params:"id" binding:"required"`
+	Enabled bool   `json:"enabled"`
+}
+
+type UpdateAlertRuleHandler struct {
+	usecase usecase.AlertUseCase
+}
+
+func NewUpdateAlertRuleHandler(usecase usecase.AlertUseCase) *UpdateAlertRuleHandler {
+	return &UpdateAlertRuleHandler{usecase: usecase}
+}
+
+func (h *UpdateAlertRuleHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *UpdateAlertRuleRequest) (*AlertRuleResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	return h.usecase.SetRuleEnabled(ctx, userData.UserID, req.ID, req.Enabled)
+}
+
+type DeleteAlertRuleRequest struct {
+	ID string `params:"id" binding:"required"`
+}
+
+type DeleteAlertRuleResponse struct {
+	Message string `json:"message"`
+}
+
+type DeleteAlertRuleHandler struct {
+	usecase usecase.AlertUseCase
+}
+
+func NewDeleteAlertRuleHandler(usecase usecase.AlertUseCase) *DeleteAlertRuleHandler {
+	return &DeleteAlertRuleHandler{usecase: usecase}
+}
+
+func (h *DeleteAlertRuleHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	if err := h.usecase.DeleteRule(ctx, userData.UserID, req.ID); err != nil {
+		return nil, err
+	}
+	return &DeleteAlertRuleResponse{Message: "Alarm kuralı silindi"}, nil
+}
+
+type AlertsRequest struct {
+	RuleID   string `query:"rule_id"`
+	Streamer string `query:"streamer"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit"`
+}
+
+type AlertsResponse = usecase.AlertPage
+
+type AlertsHandler struct {
+	usecase usecase.AlertUseCase
+}
+
+func NewAlertsHandler(usecase usecase.AlertUseCase) *AlertsHandler {
+	return &AlertsHandler{usecase: usecase}
+}
+
+func (h *AlertsHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *AlertsRequest) (*AlertsResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	return h.usecase.ListAlerts(ctx, userData.UserID, usecase.AlertQuery{
+		RuleID:   req.RuleID,
+		Streamer: req.Streamer,
+		Cursor:   req.Cursor,
+		Limit:    req.Limit,
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"kick-chat/domain"
+	"kick-chat/handler"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportRequest struct {
+	ListenerID string     `query:"listener_id"`
+	Streamer   string     `query:"streamer"`
+	Sender     string     `query:"sender"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+	// Format is csv (default), jsonl, html, or vtt / srt subtitles
+	Format string `query:"format"`
+
+	// Subtitle options: start is when the broadcast began (defaults to from),
+	// cue_duration a Go duration such as "6s", sender_format color, plain or none
+	Start        *time.Time `query:"start"`
+	CueDuration  string     `query:"cue_duration"`
+	MaxLines     int        `query:"max_lines"`
+	SenderFormat string     `query:"sender_format"`
+}
+
+type ExportHandler struct {
+	usecase usecase.MessageExportUseCase
+}
+
+func NewExportHandler(usecase usecase.MessageExportUseCase) *ExportHandler {
+	return &ExportHandler{usecase: usecase}
+}
+
+func (h *ExportHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *ExportRequest) (*handler.Stream, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	var cueDuration time.Duration
+	if req.CueDuration != "" {
+		var err error
+		if cueDuration, err = parseListenDuration(req.CueDuration); err != nil {
+			return nil, err
+		}
+	}
+
+	export, err := h.usecase.Open(ctx, userData.UserID, usecase.ExportQuery{
+		ListenerID: req.ListenerID,
+		Streamer:   req.Streamer,
+		Sender:     req.Sender,
+		From:       req.From,
+		To:         req.To,
+		Format:     req.Format,
+		Subtitles: usecase.SubtitleOptions{
+			Start:        req.Start,
+			CueDuration:  cueDuration,
+			MaxLines:     req.MaxLines,
+			SenderFormat: req.SenderFormat,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &handler.Stream{
+		ContentType: export.ContentType,
+		FileName:    export.FileName,
+		Write: func(ctx context.Context, w io.Writer) error {
+			_, err := export.WriteTo(ctx, w)
+			return err
+		},
+	}, nil
+}
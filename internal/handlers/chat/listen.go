@@ -3,13 +3,20 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"kick-chat/domain"
 	usecase "kick-chat/internal/usecases/chat"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type ListenRequest struct {
 	UserName string `params:"username" binding:"required"`
+	// Duration is a Go duration string such as "90m" or "2h"; empty uses the server default
+	Duration string `json:"duration"`
+	// Sinks limits where this request's events go, e.g. ["console","archive"]; empty means all.
+	// Storage, alerts and user webhooks always run.
+	Sinks []string `json:"sinks"`
 }
 
 type ListenResponse struct {
@@ -27,10 +34,78 @@ func NewListenHandler(usecase usecase.ListenUseCase) *ListenHandler {
 
 func (h *ListenHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *ListenRequest) (*ListenResponse, error) {
 	fmt.Println("listen user:", req.UserName)
-	message, err := h.usecase.Execute(fbrCtx, ctx, req.UserName)
+	var duration time.Duration
+	if req.Duration != "" {
+		var err error
+		if duration, err = parseListenDuration(req.Duration); err != nil {
+			return nil, err
+		}
+	}
+
+	message, err := h.usecase.Execute(fbrCtx, ctx, req.UserName, duration, req.Sinks)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ListenResponse{Message: message}, nil
 }
+
+type ListenAdjustRequest struct {
+	UserName string `params:"username" binding:"required"`
+	Duration string `json:"duration" binding:"required"`
+}
+
+type ListenAdjustResponse = usecase.ListenRequestStatus
+
+// ListenAdjustHandler serves both extend and shorten, which only differ in direction
+type ListenAdjustHandler struct {
+	usecase usecase.ListenUseCase
+	shorten bool
+}
+
+func NewListenExtendHandler(usecase usecase.ListenUseCase) *ListenAdjustHandler {
+	return &ListenAdjustHandler{usecase: usecase}
+}
+
+func NewListenShortenHandler(usecase usecase.ListenUseCase) *ListenAdjustHandler {
+	return &ListenAdjustHandler{usecase: usecase, shorten: true}
+}
+
+func (h *ListenAdjustHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *ListenAdjustRequest) (*ListenAdjustResponse, error) {
+	by, err := parseListenDuration(req.Duration)
+	if err != nil {
+		return nil, err
+	}
+	if h.shorten {
+		return h.usecase.Shorten(fbrCtx, ctx, req.UserName, by)
+	}
+	return h.usecase.Extend(fbrCtx, ctx, req.UserName, by)
+}
+
+func parseListenDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: geçersiz süre %q", domain.ErrInvalidInput, s)
+	}
+	return d, nil
+}
+
+type UnsubscribeRequest struct {
+	UserName string `params:"username" binding:"required"`
+}
+
+type UnsubscribeResponse = usecase.UnsubscribeResult
+
+type UnsubscribeHandler struct {
+	usecase usecase.ListenUseCase
+}
+
+func NewUnsubscribeHandler(usecase usecase.ListenUseCase) *UnsubscribeHandler {
+	return &UnsubscribeHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *UnsubscribeHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *UnsubscribeRequest) (*UnsubscribeResponse, error) {
+	return h.usecase.Unsubscribe(fbrCtx, ctx, req.UserName)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"kick-chat/domain"
+	"kick-chat/handler"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseRetryMillis       = 3000
+)
+
+type LiveSSEHandler struct {
+	usecase usecase.LiveFeedUseCase
+}
+
+func NewLiveSSEHandler(usecase usecase.LiveFeedUseCase) *LiveSSEHandler {
+	return &LiveSSEHandler{
+		usecase: usecase,
+	}
+}
+
+// Streamer serves GET /sse/listen/:username
+func (h *LiveSSEHandler) Streamer(c *fiber.Ctx) error {
+	userData, ok := middleware.GetUserData(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": domain.ErrNotFoundAuthorization.Error()})
+	}
+	return h.stream(c, userData.UserID, []string{c.Params("username")})
+}
+
+// Combined serves GET /sse/listen, every streamer the signed-in user listens to
+func (h *LiveSSEHandler) Combined(c *fiber.Ctx) error {
+	userData, ok := middleware.GetUserData(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": domain.ErrNotFoundAuthorization.Error()})
+	}
+
+	streamers, err := h.usecase.OwnedStreamers(c.UserContext(), userData.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(streamers) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "no active listeners"})
+	}
+	return h.stream(c, userData.UserID, streamers)
+}
+
+func (h *LiveSSEHandler) stream(c *fiber.Ctx, userID string, streamers []string) error {
+	// EventSource sends Last-Event-ID on reconnect; allow a query param for the first connect
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	// Subscribe before reading the store so nothing falls between replay and live
+	sub, backlog, err := h.usecase.Join(c.UserContext(), userID, streamers...)
+	if err != nil {
+		return c.Status(handler.StatusFor(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	replay, err := h.usecase.Resume(c.UserContext(), streamers, lastEventID)
+	if err != nil {
+		h.usecase.Leave(sub)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	backlog = backlogAfter(backlog, lastEventID)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.usecase.Leave(sub)
+
+		flush := func() error {
+			// The server WriteTimeout would otherwise cut long lived streams
+			conn.SetWriteDeadline(time.Now().Add(sseHeartbeatInterval * 2))
+			return w.Flush()
+		}
+
+		fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+		sent := make(map[string]struct{}, len(replay))
+		for _, event := range replay {
+			writeSSEEvent(w, event)
+			sent[event.ID] = struct{}{}
+		}
+		for _, event := range backlog {
+			if _, dup := sent[event.ID]; dup && event.ID != "" {
+				continue
+			}
+			writeSSEEvent(w, event)
+		}
+		if err := flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, open := <-sub.C:
+				if !open {
+					if sub.Slow() {
+						fmt.Fprint(w, "event: error\ndata: {\"error\":\"slow consumer\"}\n\n")
+						flush()
+					}
+					return
+				}
+				if _, dup := sent[event.ID]; dup && event.ID != "" {
+					continue
+				}
+				writeSSEEvent(w, event)
+				if err := flush(); err != nil {
+					return
+				}
+
+			case <-heartbeat.C:
+				fmt.Fprintf(w, ": heartbeat %d\n\n", time.Now().Unix())
+				if err := flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// backlogAfter drops backlog events the client already saw before reconnecting.
+// When lastEventID has already left the backlog, the stored replay from Resume is
+// all the client gets; resending the whole backlog would repeat what it has seen.
+func backlogAfter(backlog []usecase.LiveEvent, lastEventID string) []usecase.LiveEvent {
+	if lastEventID == "" {
+		return backlog
+	}
+	for i, event := range backlog {
+		if event.ID == lastEventID {
+			return backlog[i+1:]
+		}
+	}
+	return nil
+}
+
+func writeSSEEvent(w *bufio.Writer, event usecase.LiveEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	if event.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+}
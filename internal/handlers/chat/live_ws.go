@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+	"log"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	liveWriteTimeout = 10 * time.Second
+	livePongTimeout  = 60 * time.Second
+	livePingInterval = 25 * time.Second
+)
+
+type LiveWSHandler struct {
+	usecase usecase.LiveFeedUseCase
+}
+
+func NewLiveWSHandler(usecase usecase.LiveFeedUseCase) *LiveWSHandler {
+	return &LiveWSHandler{
+		usecase: usecase,
+	}
+}
+
+// Upgrade rejects plain HTTP requests before the websocket handler runs
+func (h *LiveWSHandler) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	return c.Next()
+}
+
+func (h *LiveWSHandler) Handle() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		userData, ok := middleware.GetUserDataFromWS(conn)
+		if !ok {
+			writeClose(conn, websocket.ClosePolicyViolation, "unauthorized")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		sub, backlog, err := h.usecase.Join(ctx, userData.UserID, conn.Params("username"))
+		cancel()
+		if err != nil {
+			writeClose(conn, websocket.ClosePolicyViolation, err.Error())
+			return
+		}
+		defer h.usecase.Leave(sub)
+
+		// Reader: we don't expect client messages, but must consume control frames
+		clientGone := make(chan struct{})
+		go func() {
+			defer close(clientGone)
+			conn.SetReadDeadline(time.Now().Add(livePongTimeout))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(livePongTimeout))
+			})
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		for _, event := range backlog {
+			if err := writeEvent(conn, event); err != nil {
+				return
+			}
+		}
+
+		ping := time.NewTicker(livePingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case event, open := <-sub.C:
+				if !open {
+					if sub.Slow() {
+						writeClose(conn, websocket.ClosePolicyViolation, "slow consumer")
+					}
+					return
+				}
+				if err := writeEvent(conn, event); err != nil {
+					log.Printf("'%s' canlı yayın istemcisine yazılamadı: %v", conn.Params("username"), err)
+					return
+				}
+
+			case <-ping.C:
+				conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+
+			case <-clientGone:
+				return
+			}
+		}
+	})
+}
+
+func writeEvent(conn *websocket.Conn, event usecase.LiveEvent) error {
+	conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	return conn.WriteJSON(event)
+}
+
+func writeClose(conn *websocket.Conn, code int, reason string) {
+	conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+}
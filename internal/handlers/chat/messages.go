@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MessagesRequest struct {
+	ListenerID string     `params:"id" binding:"required"`
+	Sender     string     `query:"sender"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+	HasLink    *bool      `query:"has_link"`
+	Contains   string     `query:"contains"`
+	Cursor     string     `query:"cursor"`
+	Order      string     `query:"order"`
+	Limit      int        `query:"limit"`
+}
+
+type MessagesResponse = usecase.MessagePage
+
+type MessagesHandler struct {
+	usecase usecase.MessageHistoryUseCase
+}
+
+func NewMessagesHandler(usecase usecase.MessageHistoryUseCase) *MessagesHandler {
+	return &MessagesHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *MessagesHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *MessagesRequest) (*MessagesResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	return h.usecase.List(ctx, userData.UserID, usecase.MessageQuery{
+		ListenerID: req.ListenerID,
+		Sender:     req.Sender,
+		From:       req.From,
+		To:         req.To,
+		HasLink:    req.HasLink,
+		Contains:   req.Contains,
+		Cursor:     req.Cursor,
+		Order:      req.Order,
+		Limit:      req.Limit,
+	})
+}
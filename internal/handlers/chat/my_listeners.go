@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MyListenersRequest struct{}
+
+type MyListenersResponse struct {
+	Listeners []usecase.MyListener `json:"listeners"`
+}
+
+type MyListenersHandler struct {
+	usecase usecase.MyListenersUseCase
+}
+
+func NewMyListenersHandler(usecase usecase.MyListenersUseCase) *MyListenersHandler {
+	return &MyListenersHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *MyListenersHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *MyListenersRequest) (*MyListenersResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	listeners, err := h.usecase.List(ctx, userData.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &MyListenersResponse{Listeners: listeners}, nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchRequest struct {
+	Query     string     `query:"q" binding:"required"`
+	Streamers string     `query:"streamer"`
+	Sender    string     `query:"sender"`
+	From      *time.Time `query:"from"`
+	To        *time.Time `query:"to"`
+	Page      int        `query:"page"`
+	Limit     int        `query:"limit"`
+}
+
+type SearchResponse = usecase.SearchPage
+
+type SearchHandler struct {
+	usecase usecase.MessageSearchUseCase
+}
+
+func NewSearchHandler(usecase usecase.MessageSearchUseCase) *SearchHandler {
+	return &SearchHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *SearchHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	return h.usecase.Search(ctx, userData.UserID, usecase.SearchQuery{
+		Query:     req.Query,
+		Streamers: req.Streamers,
+		Sender:    req.Sender,
+		From:      req.From,
+		To:        req.To,
+		Page:      req.Page,
+		Limit:     req.Limit,
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	usecase "kick-chat/internal/usecases/chat"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookResponse = domain.WebhookEndpoint
+
+type CreateWebhookRequest struct {
+	URL string `json:"url" binding:"required"`
+	// Format is json (signed raw event, default), discord or slack
+	Format string `json:"format"`
+	// Events defaults to message, link and alert
+	Events []string `json:"events"`
+	// Streamer scopes the endpoint to one channel, empty means every channel the user listens to
+	Streamer        string `json:"streamer"`
+	RateLimitPerMin *int   `json:"rate_limit_per_minute"`
+}
+
+type CreateWebhookHandler struct {
+	usecase usecase.WebhookUseCase
+}
+
+func NewCreateWebhookHandler(usecase usecase.WebhookUseCase) *CreateWebhookHandler {
+	return &CreateWebhookHandler{usecase: usecase}
+}
+
+func (h *CreateWebhookHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *CreateWebhookRequest) (*WebhookResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	return h.usecase.CreateEndpoint(ctx, userData.UserID, usecase.WebhookEndpointInput{
+		URL:             req.URL,
+		Format:          req.Format,
+		Events:          req.Events,
+		Streamer:        req.Streamer,
+		RateLimitPerMin: req.RateLimitPerMin,
+	})
+}
+
+type WebhooksRequest struct{}
+
+type WebhooksResponse struct {
+	Webhooks []domain.WebhookEndpoint `json:"webhooks"`
+}
+
+type WebhooksHandler struct {
+	usecase usecase.WebhookUseCase
+}
+
+func NewWebhooksHandler(usecase usecase.WebhookUseCase) *WebhooksHandler {
+	return &WebhooksHandler{usecase: usecase}
+}
+
+func (h *WebhooksHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *WebhooksRequest) (*WebhooksResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	endpoints, err := h.usecase.ListEndpoints(ctx, userData.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhooksResponse{Webhooks: endpoints}, nil
+}
+
+type DeleteWebhookRequest struct {
+	ID string `params:"id" binding:"required"`
+}
+
+type WebhookMessageResponse struct {
+	Message string `json:"message"`
+}
+
+type DeleteWebhookHandler struct {
+	usecase usecase.WebhookUseCase
+}
+
+func NewDeleteWebhookHandler(usecase usecase.WebhookUseCase) *DeleteWebhookHandler {
+	return &DeleteWebhookHandler{usecase: usecase}
+}
+
+func (h *DeleteWebhookHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *DeleteWebhookRequest) (*WebhookMessageResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	if err := h.usecase.DeleteEndpoint(ctx, userData.UserID, req.ID); err != nil {
+		return nil, err
+	}
+	return &WebhookMessageResponse{Message: "Webhook silindi"}, nil
+}
+
+type WebhookDeliveriesRequest struct {
+	ID     string `params:"id" binding:"required"`
+	Status string `query:"status"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+type WebhookDeliveriesResponse = usecase.WebhookDeliveryPage
+
+type WebhookDeliveriesHandler struct {
+	usecase usecase.WebhookUseCase
+}
+
+func NewWebhookDeliveriesHandler(usecase usecase.WebhookUseCase) *WebhookDeliveriesHandler {
+	return &WebhookDeliveriesHandler{usecase: usecase}
+}
+
+func (h *WebhookDeliveriesHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *WebhookDeliveriesRequest) (*WebhookDeliveriesResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	return h.usecase.ListDeliveries(ctx, userData.UserID, req.ID, usecase.WebhookDeliveryQuery{
+		Status: req.Status,
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+	})
+}
+
+type RedeliverWebhookRequest struct {
+	ID         string `params:"id" binding:"required"`
+	DeliveryID string `params:"delivery_id" binding:"required"`
+}
+
+type RedeliverWebhookHandler struct {
+	usecase usecase.WebhookUseCase
+}
+
+func NewRedeliverWebhookHandler(usecase usecase.WebhookUseCase) *RedeliverWebhookHandler {
+	return &RedeliverWebhookHandler{usecase: usecase}
+}
+
+func (h *RedeliverWebhookHandler) Handle(fbrCtx *fiber.Ctx, ctx context.Context, req *RedeliverWebhookRequest) (*WebhookMessageResponse, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	if err := h.usecase.Redeliver(ctx, userData.UserID, req.ID, req.DeliveryID); err != nil {
+		return nil, err
+	}
+	return &WebhookMessageResponse{Message: "Teslimat tekrar kuyruğa alındı"}, nil
+}
@@ -7,8 +7,12 @@ import (
 	"log"
 )
 
+func DatabaseURL(appConfig *config.Config) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", appConfig.Postgres.User, appConfig.Postgres.Password, appConfig.Postgres.Host, appConfig.Postgres.Port, appConfig.Postgres.DB)
+}
+
 func InitDatabase(appConfig *config.Config) *postgres.Repository {
-	databaseURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", appConfig.Postgres.User, appConfig.Postgres.Password, appConfig.Postgres.Host, appConfig.Postgres.Port, appConfig.Postgres.DB)
+	databaseURL := DatabaseURL(appConfig)
 	fmt.Println(databaseURL)
 	repo, err := postgres.NewRepository(databaseURL)
 	if err != nil {
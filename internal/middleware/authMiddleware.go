@@ -57,8 +57,7 @@ func GetUserData(c *fiber.Ctx) (*domain.Session, bool) {
 	userData, ok := c.Locals("userData").(*domain.Session)
 	return userData, ok
 }
-func GetUserDataFromWS(conn *websocket.Conn) (map[string]string, bool) {
-	val := conn.Locals("userData")
-	userData, ok := val.(map[string]string)
+func GetUserDataFromWS(conn *websocket.Conn) (*domain.Session, bool) {
+	userData, ok := conn.Locals("userData").(*domain.Session)
 	return userData, ok
 }
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"kick-chat/domain"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AlertEngineRepository interface {
+	GetEnabledAlertRules(ctx context.Context) ([]domain.AlertRule, error)
+	InsertAlert(ctx context.Context, alert domain.Alert) (*domain.Alert, error)
+}
+
+// AlertEngine evaluates every chat message against the enabled alert rules. It
+// runs as a sink, so matching never slows the listener down.
+type AlertEngine struct {
+	repo     AlertEngineRepository
+	exporter *BusExporter
+	refresh  time.Duration
+	rules    atomic.Pointer[alertRuleSet]
+
+	mu        sync.Mutex
+	lastFired map[uuid.UUID]time.Time
+	hooks     []func(domain.Alert)
+
+	fired atomic.Int64
+}
+
+func NewAlertEngine(repo AlertEngineRepository, exporter *BusExporter, config *Config) *AlertEngine {
+	e := &AlertEngine{
+		repo:      repo,
+		exporter:  exporter,
+		refresh:   config.AlertRulesRefresh,
+		lastFired: make(map[uuid.UUID]time.Time),
+	}
+	e.rules.Store(newAlertRuleSet(nil))
+	return e
+}
+
+// OnAlert registers a hook called for every stored alert, on the sink goroutine
+func (e *AlertEngine) OnAlert(hook func(domain.Alert)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hooks = append(e.hooks, hook)
+}
+
+// Reload recompiles the rule set; rules edited on another replica arrive on the next refresh
+func (e *AlertEngine) Reload(ctx context.Context) error {
+	rules, err := e.repo.GetEnabledAlertRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compileAlertRule(rule)
+		if err != nil {
+			log.Printf("Alarm kuralı %s derlenemedi: %v", rule.ID, err)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	e.rules.Store(newAlertRuleSet(compiled))
+	return nil
+}
+
+func (e *AlertEngine) Run(ctx context.Context) {
+	if err := e.Reload(ctx); err != nil {
+		log.Printf("Alarm kuralları yüklenemedi: %v", err)
+	}
+
+	ticker := time.NewTicker(e.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Reload(ctx); err != nil {
+				log.Printf("Alarm kuralları yenilenemedi: %v", err)
+			}
+		}
+	}
+}
+
+// Global keeps alerts on even for listeners whose requests picked other sinks,
+// the rules belong to moderators, not to whoever started the listener
+func (e *AlertEngine) Global() bool {
+	return true
+}
+
+func (e *AlertEngine) Write(ctx context.Context, event SinkEvent) error {
+	data, ok := event.Event.(*Data)
+	if !ok {
+		return nil
+	}
+	global, scoped := e.rules.Load().rulesFor(event.Streamer)
+	if len(global) == 0 && len(scoped) == 0 {
+		return nil
+	}
+	// Like history and export, a user only sees chat of streamers they listen to
+	listener, ok := ListenerManager.GetListener(event.Streamer)
+	if !ok {
+		return nil
+	}
+
+	msg := newAlertMessage(data.Content)
+	var errs []error
+	for _, rules := range [][]compiledRule{global, scoped} {
+		for _, rule := range rules {
+			if !listener.HasUserRequest(rule.rule.UserID) {
+				continue
+			}
+			matched, ok := rule.match(msg)
+			if !ok || !e.allow(rule.rule, event.ReceivedAt) {
+				continue
+			}
+			if err := e.fire(ctx, rule.rule, event, data, matched); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			e.markFired(rule.rule.ID, event.ReceivedAt)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// allow applies the rule's cooldown
+func (e *AlertEngine) allow(rule domain.AlertRule, at time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+	last, ok := e.lastFired[rule.ID]
+	return !ok || at.Sub(last) >= cooldown
+}
+
+// markFired starts the cooldown, only once the alert was stored so a failed
+// insert lets the next match try again
+func (e *AlertEngine) markFired(ruleID uuid.UUID, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastFired[ruleID] = at
+}
+
+func (e *AlertEngine) fire(ctx context.Context, rule domain.AlertRule, event SinkEvent, data *Data, matched string) error {
+	alert, err := e.repo.InsertAlert(ctx, domain.Alert{
+		RuleID:         rule.ID,
+		UserID:         rule.UserID,
+		Streamer:       event.Streamer,
+		KickMessageID:  data.ID,
+		SenderUsername: data.Sender.Username,
+		Content:        data.Content,
+		Matched:        matched,
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil // rule deleted since the last reload
+	}
+	if err != nil {
+		return err
+	}
+
+	e.fired.Add(1)
+	if e.exporter != nil {
+		e.exporter.PublishRaw(event.Streamer, "alert", alert, alert.CreatedAt)
+	}
+
+	e.mu.Lock()
+	hooks := e.hooks
+	e.mu.Unlock()
+	for _, hook := range hooks {
+		hook(*alert)
+	}
+	return nil
+}
+
+func (e *AlertEngine) Fired() int64 {
+	return e.fired.Load()
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"fmt"
+	"kick-chat/domain"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// normalizeTurkish folds case and Turkish diacritics rune for rune, so "İSTANBUL",
+// "istanbul" and "Istanbul" all become "istanbul" and offsets still line up
+// with the original text.
+func normalizeTurkish(s string) string {
+	return strings.Map(foldTurkish, s)
+}
+
+func foldTurkish(r rune) rune {
+	switch r {
+	case 'I', 'İ', 'ı', 'î', 'Î':
+		return 'i'
+	case 'ç', 'Ç':
+		return 'c'
+	case 'ğ', 'Ğ':
+		return 'g'
+	case 'ö', 'Ö':
+		return 'o'
+	case 'ş', 'Ş':
+		return 's'
+	case 'ü', 'Ü', 'û', 'Û':
+		return 'u'
+	case 'â', 'Â':
+		return 'a'
+	}
+	return unicode.ToLower(r)
+}
+
+// foldRegexPattern folds only non-ASCII letters: lowercasing ASCII would turn
+// escapes like \S or \W into their opposites, and (?i) covers ASCII case anyway
+func foldRegexPattern(pattern string) string {
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf {
+			return r
+		}
+		return foldTurkish(r)
+	}, pattern)
+}
+
+// compiledRule is a rule with its pattern prepared for matching normalized text
+type compiledRule struct {
+	rule   domain.AlertRule
+	needle []rune
+	re     *regexp.Regexp
+}
+
+func compileAlertRule(rule domain.AlertRule) (compiledRule, error) {
+	c := compiledRule{rule: rule}
+	switch rule.Kind {
+	case domain.AlertKindKeyword, domain.AlertKindLink:
+		c.needle = []rune(normalizeTurkish(rule.Pattern))
+	case domain.AlertKindRegex:
+		re, err := regexp.Compile("(?i)" + foldRegexPattern(rule.Pattern))
+		if err != nil {
+			return c, fmt.Errorf("%w: geçersiz regex: %v", domain.ErrInvalidInput, err)
+		}
+		c.re = re
+	default:
+		return c, fmt.Errorf("%w: bilinmeyen kural tipi: %s", domain.ErrInvalidInput, rule.Kind)
+	}
+	return c, nil
+}
+
+// alertMessage is a message prepared once and matched against every rule
+type alertMessage struct {
+	original   []rune
+	normalized string
+	runes      []rune
+	links      []string
+}
+
+func newAlertMessage(content string) alertMessage {
+	normalized := normalizeTurkish(content)
+	return alertMessage{
+		original:   []rune(content),
+		normalized: normalized,
+		runes:      []rune(normalized),
+		links:      linkRegex.FindAllString(content, -1),
+	}
+}
+
+// match returns the matched part of the original message
+func (c compiledRule) match(msg alertMessage) (string, bool) {
+	switch c.rule.Kind {
+	case domain.AlertKindKeyword:
+		if i := indexWord(msg.runes, c.needle); i >= 0 {
+			return string(msg.original[i : i+len(c.needle)]), true
+		}
+	case domain.AlertKindRegex:
+		if loc := c.re.FindStringIndex(msg.normalized); loc != nil {
+			start := utf8.RuneCountInString(msg.normalized[:loc[0]])
+			end := start + utf8.RuneCountInString(msg.normalized[loc[0]:loc[1]])
+			return string(msg.original[start:end]), true
+		}
+	case domain.AlertKindLink:
+		for _, link := range msg.links {
+			// "*" fires on any link
+			if string(c.needle) == "*" || strings.Contains(normalizeTurkish(link), string(c.needle)) {
+				return link, true
+			}
+		}
+	}
+	return "", false
+}
+
+// indexWord finds needle as a whole word (or phrase), -1 when absent
+func indexWord(haystack, needle []rune) int {
+	if len(needle) == 0 {
+		return -1
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if !runesEqual(haystack[i:i+len(needle)], needle) {
+			continue
+		}
+		if i > 0 && isWordRune(haystack[i-1]) {
+			continue
+		}
+		if end := i + len(needle); end < len(haystack) && isWordRune(haystack[end]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// alertRuleSet indexes rules by streamer; global rules apply to every channel
+type alertRuleSet struct {
+	global     []compiledRule
+	byStreamer map[string][]compiledRule
+}
+
+func newAlertRuleSet(rules []compiledRule) *alertRuleSet {
+	set := &alertRuleSet{byStreamer: make(map[string][]compiledRule)}
+	for _, rule := range rules {
+		if rule.rule.Streamer == nil {
+			set.global = append(set.global, rule)
+			continue
+		}
+		streamer := strings.ToLower(*rule.rule.Streamer)
+		set.byStreamer[streamer] = append(set.byStreamer[streamer], rule)
+	}
+	return set
+}
+
+func (s *alertRuleSet) rulesFor(streamer string) ([]compiledRule, []compiledRule) {
+	return s.global, s.byStreamer[strings.ToLower(streamer)]
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type AlertRepository interface {
+	CreateAlertRule(ctx context.Context, rule domain.AlertRule) (*domain.AlertRule, error)
+	CountAlertRules(ctx context.Context, userID uuid.UUID) (int, error)
+	GetUserAlertRules(ctx context.Context, userID uuid.UUID) ([]domain.AlertRule, error)
+	SetAlertRuleEnabled(ctx context.Context, userID, ruleID uuid.UUID, enabled bool) (*domain.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, userID, ruleID uuid.UUID) error
+	GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.Alert, error)
+}
+
+type AlertRuleInput struct {
+	Streamer        string
+	Kind            string
+	Pattern         string
+	CooldownSeconds *int
+}
+
+type AlertQuery struct {
+	RuleID   string
+	Streamer string
+	Cursor   string
+	Limit    int
+}
+
+type AlertPage struct {
+	Alerts     []domain.Alert `json:"alerts"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	HasMore    bool           `json:"has_more"`
+}
+
+type AlertUseCase interface {
+	CreateRule(ctx context.Context, userID string, input AlertRuleInput) (*domain.AlertRule, error)
+	ListRules(ctx context.Context, userID string) ([]domain.AlertRule, error)
+	SetRuleEnabled(ctx context.Context, userID, ruleID string, enabled bool) (*domain.AlertRule, error)
+	DeleteRule(ctx context.Context, userID, ruleID string) error
+	ListAlerts(ctx context.Context, userID string, query AlertQuery) (*AlertPage, error)
+}
+
+type alertUseCase struct {
+	repo   AlertRepository
+	engine *AlertEngine
+	config *Config
+}
+
+func NewAlertUseCase(repo AlertRepository, engine *AlertEngine) AlertUseCase {
+	return &alertUseCase{
+		repo:   repo,
+		engine: engine,
+		config: AppConfig,
+	}
+}
+
+func parseIDs(userID, ruleID string) (uuid.UUID, uuid.UUID, error) {
+	user, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrNotFoundAuthorization
+	}
+	rule, err := uuid.Parse(ruleID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%w: geçersiz kural id", domain.ErrInvalidInput)
+	}
+	return user, rule, nil
+}
+
+func (u *alertUseCase) CreateRule(ctx context.Context, userID string, input AlertRuleInput) (*domain.AlertRule, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	rule := domain.AlertRule{
+		UserID:          currentUserID,
+		Kind:            strings.ToLower(strings.TrimSpace(input.Kind)),
+		Pattern:         strings.TrimSpace(input.Pattern),
+		CooldownSeconds: u.config.AlertDefaultCooldown,
+		Enabled:         true,
+	}
+	if streamer := strings.ToLower(strings.TrimSpace(input.Streamer)); streamer != "" {
+		rule.Streamer = &streamer
+	}
+	if input.CooldownSeconds != nil {
+		rule.CooldownSeconds = *input.CooldownSeconds
+	}
+
+	if rule.Pattern == "" {
+		return nil, fmt.Errorf("%w: pattern boş olamaz", domain.ErrInvalidInput)
+	}
+	if len([]rune(rule.Pattern)) > u.config.AlertMaxPatternLength {
+		return nil, fmt.Errorf("%w: pattern en fazla %d karakter olabilir", domain.ErrInvalidInput, u.config.AlertMaxPatternLength)
+	}
+	if rule.CooldownSeconds < 0 || rule.CooldownSeconds > u.config.AlertMaxCooldown {
+		return nil, fmt.Errorf("%w: cooldown 0 ile %d saniye arasında olmalı", domain.ErrInvalidInput, u.config.AlertMaxCooldown)
+	}
+	// Compiling here rejects bad kinds and regexes before they reach the database
+	if _, err := compileAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	count, err := u.repo.CountAlertRules(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= u.config.AlertMaxRulesPerUser {
+		return nil, fmt.Errorf("%w: en fazla %d alarm kuralı tanımlanabilir", domain.ErrQuotaExceeded, u.config.AlertMaxRulesPerUser)
+	}
+
+	created, err := u.repo.CreateAlertRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+	u.reload(ctx)
+	return created, nil
+}
+
+func (u *alertUseCase) ListRules(ctx context.Context, userID string) ([]domain.AlertRule, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	rules, err := u.repo.GetUserAlertRules(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []domain.AlertRule{}
+	}
+	return rules, nil
+}
+
+func (u *alertUseCase) SetRuleEnabled(ctx context.Context, userID, ruleID string, enabled bool) (*domain.AlertRule, error) {
+	currentUserID, id, err := parseIDs(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := u.repo.SetAlertRuleEnabled(ctx, currentUserID, id, enabled)
+	if err != nil {
+		return nil, err
+	}
+	u.reload(ctx)
+	return rule, nil
+}
+
+func (u *alertUseCase) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	currentUserID, id, err := parseIDs(userID, ruleID)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.DeleteAlertRule(ctx, currentUserID, id); err != nil {
+		return err
+	}
+	u.reload(ctx)
+	return nil
+}
+
+// reload applies a change on this replica right away, others pick it up on refresh
+func (u *alertUseCase) reload(ctx context.Context) {
+	if err := u.engine.Reload(ctx); err != nil {
+		log.Printf("Alarm kuralları yenilenemedi: %v", err)
+	}
+}
+
+func (u *alertUseCase) ListAlerts(ctx context.Context, userID string, query AlertQuery) (*AlertPage, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	filter := domain.AlertFilter{
+		UserID:   currentUserID,
+		Streamer: strings.TrimSpace(query.Streamer),
+		Limit:    query.Limit,
+	}
+	if query.RuleID != "" {
+		ruleID, err := uuid.Parse(query.RuleID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: geçersiz kural id", domain.ErrInvalidInput)
+		}
+		filter.RuleID = &ruleID
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeMessageCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Before = &cursor
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = u.config.AlertDefaultLimit
+	}
+	if filter.Limit > u.config.AlertMaxLimit {
+		filter.Limit = u.config.AlertMaxLimit
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+	alerts, err := u.repo.GetAlerts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &AlertPage{Alerts: alerts}
+	if len(alerts) > limit {
+		page.Alerts = alerts[:limit]
+		page.HasMore = true
+		last := page.Alerts[limit-1]
+		page.NextCursor = encodeMessageCursor(domain.MessageCursor{Timestamp: last.CreatedAt, ID: last.ID})
+	}
+	if page.Alerts == nil {
+		page.Alerts = []domain.Alert{}
+	}
+	return page, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff produces exponentially growing, jittered reconnect delays.
+// A connection that stays up for StableAfter closes the circuit again so the
+// next failure starts from Initial instead of the last (possibly capped) delay.
+type Backoff struct {
+	Initial     time.Duration
+	Max         time.Duration
+	Multiplier  float64
+	Jitter      float64 // 0..1, fraction of the delay that is randomised
+	StableAfter time.Duration
+
+	mu          sync.Mutex
+	attempt     int
+	connectedAt time.Time
+}
+
+func NewBackoff(config *Config) *Backoff {
+	return &Backoff{
+		Initial:     config.ReconnectBaseDelay,
+		Max:         config.ReconnectMaxDelay,
+		Multiplier:  2,
+		Jitter:      config.ReconnectJitter,
+		StableAfter: config.StableConnectionAfter,
+	}
+}
+
+// Next returns the delay before the next attempt and the 1-based attempt number
+func (b *Backoff) Next() (time.Duration, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.attempt++
+	return b.Delay(b.attempt), b.attempt
+}
+
+// Delay computes the jittered delay for a 1-based attempt without touching the counter,
+// for callers that keep the attempt count elsewhere
+func (b *Backoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	// Full range jitter around the computed delay keeps replicas from reconnecting in lockstep
+	if b.Jitter > 0 {
+		spread := delay * b.Jitter
+		delay = delay - spread + rand.Float64()*2*spread
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return time.Duration(delay)
+}
+
+// MarkConnected records the start of a healthy connection
+func (b *Backoff) MarkConnected() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connectedAt = time.Now()
+}
+
+// MarkDisconnected resets the attempt counter if the connection was stable long enough
+func (b *Backoff) MarkDisconnected() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.connectedAt.IsZero() && time.Since(b.connectedAt) >= b.StableAfter {
+		b.attempt = 0
+	}
+	b.connectedAt = time.Time{}
+}
+
+func (b *Backoff) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempt = 0
+	b.connectedAt = time.Time{}
+}
+
+func (b *Backoff) Attempt() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.attempt
+}
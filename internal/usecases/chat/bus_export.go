@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kick-chat/domain"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type BusPublisher interface {
+	PublishBatch(ctx context.Context, events []domain.OutboxEvent) error
+}
+
+type BusOutboxRepository interface {
+	InsertOutboxEvents(ctx context.Context, events []domain.OutboxEvent) error
+	DrainOutbox(ctx context.Context, limit, maxAttempts int, publish func([]domain.OutboxEvent) error) (int, int, error)
+	HasOutboxEvents(ctx context.Context) (bool, error)
+}
+
+// exportedEvent is the JSON body bus, webhook and file consumers receive
+type exportedEvent struct {
+	Streamer   string      `json:"streamer"`
+	Type       string      `json:"type"`
+	ReceivedAt time.Time   `json:"received_at"`
+	Data       interface{} `json:"data"`
+}
+
+type busLink struct {
+	MessageID string `json:"message_id"`
+	Sender    string `json:"sender"`
+	URL       string `json:"url"`
+	Content   string `json:"content"`
+}
+
+// BusExporter publishes chat events to a message broker in batches. While the
+// broker is unreachable batches go to the Postgres outbox, and new events keep
+// following them there until it is drained, so consumers see them in order.
+type BusExporter struct {
+	publisher     BusPublisher
+	repo          BusOutboxRepository
+	batchSize     int
+	flushInterval time.Duration
+	retryInterval time.Duration
+	drainLimit    int
+	maxAttempts   int
+	queue         chan domain.OutboxEvent
+
+	// backlogged is set while the outbox may hold rows; starts true to pick up
+	// whatever a previous run left behind
+	backlogged atomic.Bool
+
+	published atomic.Int64
+	outboxed  atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+	dead      atomic.Int64
+}
+
+type BusExporterStats struct {
+	Published  int64 `json:"published"`
+	Outboxed   int64 `json:"outboxed"`
+	Dropped    int64 `json:"dropped"`
+	Failed     int64 `json:"failed"`
+	Dead       int64 `json:"dead"`
+	Pending    int   `json:"pending"`
+	Backlogged bool  `json:"backlogged"`
+}
+
+func NewBusExporter(publisher BusPublisher, repo BusOutboxRepository, config *Config) *BusExporter {
+	e := &BusExporter{
+		publisher:     publisher,
+		repo:          repo,
+		batchSize:     config.BusBatchSize,
+		flushInterval: config.BusFlushInterval,
+		retryInterval: config.OutboxRetryInterval,
+		drainLimit:    config.OutboxDrainLimit,
+		maxAttempts:   config.OutboxMaxAttempts,
+		queue:         make(chan domain.OutboxEvent, config.BusQueueSize),
+	}
+	e.backlogged.Store(true)
+	return e
+}
+
+// routingKey builds kick.<streamer>.<kind>; dots would add topic segments
+func routingKey(streamer, kind string) string {
+	return "kick." + strings.ReplaceAll(streamer, ".", "_") + "." + kind
+}
+
+// Publish queues a chat event, plus one link event per URL in a message
+func (e *BusExporter) Publish(streamer string, event ChatEvent) {
+	now := time.Now()
+	e.PublishRaw(streamer, string(event.EventType()), event, now)
+
+	if msg, ok := event.(*Data); ok {
+		for _, link := range linkRegex.FindAllString(msg.Content, -1) {
+			e.PublishRaw(streamer, "link", busLink{
+				MessageID: msg.ID,
+				Sender:    msg.Sender.Username,
+				URL:       link,
+				Content:   msg.Content,
+			}, now)
+		}
+	}
+}
+
+// PublishRaw queues any payload under kick.<streamer>.<kind>, never blocks
+func (e *BusExporter) PublishRaw(streamer, kind string, data interface{}, receivedAt time.Time) {
+	payload, err := json.Marshal(exportedEvent{Streamer: streamer, Type: kind, ReceivedAt: receivedAt, Data: data})
+	if err != nil {
+		log.Printf("'%s' için bus olayı kodlanamadı: %v", streamer, err)
+		return
+	}
+
+	select {
+	case e.queue <- domain.OutboxEvent{RoutingKey: routingKey(streamer, kind), Payload: payload, CreatedAt: receivedAt}:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+func (e *BusExporter) Stats() BusExporterStats {
+	return BusExporterStats{
+		Published:  e.published.Load(),
+		Outboxed:   e.outboxed.Load(),
+		Dropped:    e.dropped.Load(),
+		Failed:     e.failed.Load(),
+		Dead:       e.dead.Load(),
+		Pending:    len(e.queue),
+		Backlogged: e.backlogged.Load(),
+	}
+}
+
+// Run batches the queue like MessageWriter and retries the outbox every
+// OutboxRetryInterval. On ctx cancellation the queue is parked in the outbox.
+func (e *BusExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+	retryTicker := time.NewTicker(e.retryInterval)
+	defer retryTicker.Stop()
+
+	batch := make([]domain.OutboxEvent, 0, e.batchSize)
+	for {
+		select {
+		case event := <-e.queue:
+			batch = append(batch, event)
+			if len(batch) >= e.batchSize {
+				e.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-retryTicker.C:
+			e.drain()
+
+		case <-ctx.Done():
+			for len(e.queue) > 0 {
+				batch = append(batch, <-e.queue)
+			}
+			e.park(batch)
+			return
+		}
+	}
+}
+
+func (e *BusExporter) flush(batch []domain.OutboxEvent) {
+	if e.backlogged.Load() {
+		e.park(batch)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	err := e.publisher.PublishBatch(ctx, batch)
+	cancel()
+	if err == nil {
+		e.published.Add(int64(len(batch)))
+		return
+	}
+	// Only the rejected events go to the outbox, the broker already has the rest
+	var rejected *domain.RejectedEventsError
+	if errors.As(err, &rejected) {
+		e.published.Add(int64(len(batch) - len(rejected.Indexes)))
+		parked := make([]domain.OutboxEvent, 0, len(rejected.Indexes))
+		for _, i := range rejected.Indexes {
+			parked = append(parked, batch[i])
+		}
+		batch = parked
+	}
+
+	log.Printf("%d olay broker'a gönderilemedi, outbox'a alınıyor: %v", len(batch), err)
+	e.backlogged.Store(true)
+	e.park(batch)
+}
+
+// park stores a batch in the outbox for drain to publish later
+func (e *BusExporter) park(batch []domain.OutboxEvent) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.repo.InsertOutboxEvents(ctx, batch); err != nil {
+		e.failed.Add(int64(len(batch)))
+		log.Printf("%d olay outbox'a yazılamadı: %v", len(batch), err)
+		return
+	}
+	e.outboxed.Add(int64(len(batch)))
+}
+
+// drain publishes outbox rows oldest first until the table is empty or the broker fails
+func (e *BusExporter) drain() {
+	if !e.backlogged.Load() {
+		return
+	}
+
+	total := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		n, dead, err := e.repo.DrainOutbox(ctx, e.drainLimit, e.maxAttempts, func(events []domain.OutboxEvent) error {
+			return e.publisher.PublishBatch(ctx, events)
+		})
+		cancel()
+		e.published.Add(int64(n))
+		total += n
+		if dead > 0 {
+			e.dead.Add(int64(dead))
+			log.Printf("Broker'ın %d kez reddettiği %d olay event_outbox_dead tablosuna taşındı", e.maxAttempts, dead)
+		}
+		if err != nil {
+			log.Printf("Outbox boşaltılamadı: %v", err)
+			return
+		}
+		if n < e.drainLimit {
+			break
+		}
+	}
+
+	// A short pass may only mean another replica holds the remaining rows; going
+	// direct before they are published would overtake them
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	pending, err := e.repo.HasOutboxEvents(ctx)
+	cancel()
+	if err != nil {
+		log.Printf("Outbox kontrol edilemedi: %v", err)
+		return
+	}
+	if pending {
+		return
+	}
+
+	e.backlogged.Store(false)
+	if total > 0 {
+		log.Printf("Outbox boşaltıldı (%d olay), olaylar doğrudan broker'a gönderiliyor", total)
+	}
+}
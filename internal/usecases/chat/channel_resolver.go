@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ChannelProvider looks up Kick channel metadata from one source
+type ChannelProvider interface {
+	Name() string
+	Resolve(ctx context.Context, username string) (*domain.ChannelInfo, error)
+}
+
+type ChannelCache interface {
+	Get(ctx context.Context, username string) (*domain.ChannelInfo, error)
+	Set(ctx context.Context, info domain.ChannelInfo, ttl time.Duration) error
+}
+
+type ChannelStore interface {
+	GetStreamerChannel(ctx context.Context, username string) (*domain.ChannelInfo, error)
+	UpsertStreamerChannel(ctx context.Context, info domain.ChannelInfo) error
+}
+
+type guardedProvider struct {
+	provider ChannelProvider
+	breaker  *CircuitBreaker
+}
+
+// ChannelResolver tries its providers in order behind a shared cache.
+// Every provider has its own circuit breaker so a dead one is skipped quickly.
+type ChannelResolver struct {
+	providers []guardedProvider
+	cache     ChannelCache
+	store     ChannelStore
+	ttl       time.Duration
+	timeout   time.Duration
+}
+
+// NewDefaultChannelResolver wires the official Kick API, the Vercel proxy and the
+// streamers table, in that order. cache may be nil.
+func NewDefaultChannelResolver(config *Config, cache ChannelCache, store ChannelStore) *ChannelResolver {
+	client := &http.Client{Timeout: config.ResolverTimeout}
+	return NewChannelResolver(config, cache, store,
+		&kickAPIProvider{client: client},
+		&vercelProvider{client: client},
+		&streamerTableProvider{store: store},
+	)
+}
+
+func NewChannelResolver(config *Config, cache ChannelCache, store ChannelStore, providers ...ChannelProvider) *ChannelResolver {
+	guarded := make([]guardedProvider, 0, len(providers))
+	for _, p := range providers {
+		guarded = append(guarded, guardedProvider{provider: p, breaker: NewCircuitBreaker(config)})
+	}
+	return &ChannelResolver{
+		providers: guarded,
+		cache:     cache,
+		store:     store,
+		ttl:       config.ChannelCacheTTL,
+		timeout:   config.ResolverTimeout,
+	}
+}
+
+func (r *ChannelResolver) Resolve(ctx context.Context, username string) (*domain.ChannelInfo, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, fmt.Errorf("%w: kullanıcı adı boş olamaz", domain.ErrInvalidInput)
+	}
+
+	if r.cache != nil {
+		if info, err := r.cache.Get(ctx, username); err == nil {
+			return info, nil
+		} else if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Kanal önbelleği okunamadı (%s): %v", username, err)
+		}
+	}
+
+	var errs []error
+	for _, gp := range r.providers {
+		name := gp.provider.Name()
+		if !gp.breaker.Allow() {
+			errs = append(errs, fmt.Errorf("%s: %w", name, ErrCircuitOpen))
+			continue
+		}
+
+		providerCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		info, err := gp.provider.Resolve(providerCtx, username)
+		cancel()
+
+		if err == nil && info.ChatroomID == 0 {
+			err = fmt.Errorf("chat ID bulunamadı (0 döndü)")
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				// The provider answered, the username simply isn't known
+				gp.breaker.Success()
+			case ctx.Err() != nil:
+				// The caller gave up, which says nothing about the provider
+				gp.breaker.Release()
+				return nil, fmt.Errorf("'%s' için kanal bilgisi alınamadı: %w", username, ctx.Err())
+			default:
+				gp.breaker.Failure()
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		gp.breaker.Success()
+
+		info.Username = username
+		info.Source = name
+		if info.ResolvedAt.IsZero() {
+			info.ResolvedAt = time.Now()
+		}
+		r.remember(ctx, *info)
+		return info, nil
+	}
+
+	return nil, fmt.Errorf("'%s' için kanal bilgisi alınamadı: %w", username, errors.Join(errs...))
+}
+
+// remember refreshes the cache and, for remote results, the streamers table
+func (r *ChannelResolver) remember(ctx context.Context, info domain.ChannelInfo) {
+	if r.cache != nil {
+		if err := r.cache.Set(ctx, info, r.ttl); err != nil {
+			log.Printf("Kanal önbelleğe yazılamadı (%s): %v", info.Username, err)
+		}
+	}
+	if r.store != nil && info.Source != streamerTableProviderName {
+		if err := r.store.UpsertStreamerChannel(ctx, info); err != nil {
+			log.Printf("Kanal bilgisi veritabanına yazılamadı (%s): %v", info.Username, err)
+		}
+	}
+}
+
+// ProviderStates reports the circuit state of every provider, for stats endpoints
+func (r *ChannelResolver) ProviderStates() map[string]string {
+	states := make(map[string]string, len(r.providers))
+	for _, gp := range r.providers {
+		states[gp.provider.Name()] = gp.breaker.State().String()
+	}
+	return states
+}
+
+// kickChannelResponse is the subset of the channel payload both HTTP providers return
+type kickChannelResponse struct {
+	User struct {
+		ID         int    `json:"id"`
+		ProfilePic string `json:"profile_pic"`
+	} `json:"user"`
+	Chatroom struct {
+		ID int `json:"id"`
+	} `json:"chatroom"`
+}
+
+func fetchKickChannel(ctx context.Context, client *http.Client, endpoint string) (*domain.ChannelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("istek oluşturulamadı: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; kick-chat/1.0)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("istek gönderilemedi: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("istek başarısız: HTTP %d", resp.StatusCode)
+	}
+
+	var result kickChannelResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("JSON parse hatası: %w", err)
+	}
+
+	return &domain.ChannelInfo{
+		ChatroomID: result.Chatroom.ID,
+		KickUserID: result.User.ID,
+		ProfilePic: result.User.ProfilePic,
+	}, nil
+}
+
+type kickAPIProvider struct {
+	client *http.Client
+}
+
+func (p *kickAPIProvider) Name() string { return "kick_api" }
+
+func (p *kickAPIProvider) Resolve(ctx context.Context, username string) (*domain.ChannelInfo, error) {
+	return fetchKickChannel(ctx, p.client, "https://kick.com/api/v2/channels/"+url.PathEscape(username))
+}
+
+type vercelProvider struct {
+	client *http.Client
+}
+
+func (p *vercelProvider) Name() string { return "vercel_proxy" }
+
+func (p *vercelProvider) Resolve(ctx context.Context, username string) (*domain.ChannelInfo, error) {
+	return fetchKickChannel(ctx, p.client, "https://kick-api-provider.vercel.app/api/channel?username="+url.QueryEscape(username))
+}
+
+const streamerTableProviderName = "streamers_table"
+
+// streamerTableProvider serves the last known metadata when every remote source is down
+type streamerTableProvider struct {
+	store ChannelStore
+}
+
+func (p *streamerTableProvider) Name() string { return streamerTableProviderName }
+
+func (p *streamerTableProvider) Resolve(ctx context.Context, username string) (*domain.ChannelInfo, error) {
+	return p.store.GetStreamerChannel(ctx, username)
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"testing"
+	"time"
+)
+
+func newTestBreaker() *CircuitBreaker {
+	return &CircuitBreaker{FailureThreshold: 2, OpenFor: 10 * time.Millisecond}
+}
+
+// openAndWait trips the breaker and waits until it is ready for a probe
+func openAndWait(t *testing.T, b *CircuitBreaker) {
+	t.Helper()
+	b.Failure()
+	b.Failure()
+	if b.State() != CircuitOpen || b.Allow() {
+		t.Fatalf("breaker should be open after %d failures", b.FailureThreshold)
+	}
+	time.Sleep(b.OpenFor + 5*time.Millisecond)
+}
+
+func TestCircuitBreakerProbe(t *testing.T) {
+	tests := []struct {
+		name   string
+		finish func(b *CircuitBreaker)
+		state  CircuitState
+		allow  bool
+	}{
+		{"success closes", (*CircuitBreaker).Success, CircuitClosed, true},
+		{"failure reopens", (*CircuitBreaker).Failure, CircuitOpen, false},
+		{"release allows another probe", (*CircuitBreaker).Release, CircuitHalfOpen, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBreaker()
+			openAndWait(t, b)
+
+			if !b.Allow() {
+				t.Fatal("probe should be allowed once OpenFor has passed")
+			}
+			if b.Allow() {
+				t.Fatal("only one probe may run at a time")
+			}
+			tt.finish(b)
+			if got := b.State(); got != tt.state {
+				t.Errorf("state = %s, want %s", got, tt.state)
+			}
+			if got := b.Allow(); got != tt.allow {
+				t.Errorf("Allow() = %v, want %v", got, tt.allow)
+			}
+		})
+	}
+}
+
+type stubProvider struct {
+	err error
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Resolve(ctx context.Context, _ string) (*domain.ChannelInfo, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &domain.ChannelInfo{ChatroomID: 1}, nil
+}
+
+func TestChannelResolverProbeOutcomes(t *testing.T) {
+	config := &Config{ResolverFailureThreshold: 2, ResolverOpenDuration: 10 * time.Millisecond, ResolverTimeout: time.Second}
+
+	t.Run("unknown username releases the probe", func(t *testing.T) {
+		provider := &stubProvider{}
+		r := NewChannelResolver(config, nil, nil, provider)
+		breaker := r.providers[0].breaker
+		openAndWait(t, breaker)
+
+		provider.err = fmt.Errorf("kanal yok: %w", domain.ErrNotFound)
+		if _, err := r.Resolve(context.Background(), "nobody"); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Resolve error = %v, want ErrNotFound", err)
+		}
+		provider.err = nil
+		if _, err := r.Resolve(context.Background(), "somebody"); err != nil {
+			t.Fatalf("provider stayed blocked after an unknown username: %v", err)
+		}
+	})
+
+	t.Run("cancelled caller is not a provider failure", func(t *testing.T) {
+		provider := &stubProvider{err: context.Canceled}
+		r := NewChannelResolver(config, nil, nil, provider)
+		breaker := r.providers[0].breaker
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for i := 0; i < config.ResolverFailureThreshold; i++ {
+			if _, err := r.Resolve(ctx, "somebody"); !errors.Is(err, context.Canceled) {
+				t.Fatalf("Resolve error = %v, want context.Canceled", err)
+			}
+		}
+		if got := breaker.State(); got != CircuitClosed {
+			t.Errorf("state = %s, want closed", got)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// ListenerCoordinator decides which replica listens to which streamer. Leases make
+// ownership exclusive, member heartbeats feed the hash ring that spreads streamers.
+type ListenerCoordinator interface {
+	InstanceID() string
+	Heartbeat(ctx context.Context) error
+	Members(ctx context.Context) ([]string, error)
+	Acquire(ctx context.Context, streamer string) (bool, error)
+	Renew(ctx context.Context, streamers []string) ([]string, error)
+	Release(ctx context.Context, streamer string) error
+	Leave(ctx context.Context) error
+	Notify(ctx context.Context) error
+	Nudges(ctx context.Context) <-chan struct{}
+}
+
+// ownership is this replica's view of the cluster, rebuilt on every heartbeat
+type ownership struct {
+	mu   sync.RWMutex
+	ring *HashRing
+}
+
+func (o *ownership) owner(streamer string) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if o.ring == nil {
+		return ""
+	}
+	return o.ring.Owner(streamer)
+}
+
+func (o *ownership) update(replicas int, members []string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.ring != nil && o.ring.SameMembers(members) {
+		return false
+	}
+	o.ring = NewHashRing(replicas, members)
+	return true
+}
+
+// shouldOwn reports whether the ring assigns streamer to this replica. Before the
+// first successful heartbeat nobody is known, so we keep what we have.
+func (u *listenUseCase) shouldOwn(streamer string) bool {
+	if u.coord == nil {
+		return true
+	}
+	owner := u.cluster.owner(streamer)
+	return owner == "" || owner == u.coord.InstanceID()
+}
+
+// claim is shouldOwn plus the lease, so two replicas with different ring views
+// never listen to the same streamer at once
+func (u *listenUseCase) claim(ctx context.Context, streamer string) bool {
+	if u.coord == nil {
+		return true
+	}
+	if !u.shouldOwn(streamer) {
+		return false
+	}
+	ok, err := u.coord.Acquire(ctx, streamer)
+	if err != nil {
+		log.Printf("'%s' için sahiplik alınamadı: %v", streamer, err)
+		return false
+	}
+	return ok
+}
+
+func (u *listenUseCase) releaseLease(streamer string) {
+	if u.coord == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := u.coord.Release(ctx, streamer); err != nil {
+		log.Printf("'%s' için sahiplik bırakılamadı: %v", streamer, err)
+	}
+}
+
+// notifyCluster wakes the other replicas after a request changed, the owner
+// applies it on its next reconcile instead of waiting for the interval
+func (u *listenUseCase) notifyCluster(ctx context.Context) {
+	if u.coord == nil {
+		return
+	}
+	if err := u.coord.Notify(ctx); err != nil {
+		log.Printf("Eşitleme bildirimi gönderilemedi: %v", err)
+	}
+}
+
+// runCoordinator heartbeats, refreshes the ring and renews the leases of running
+// listeners. A lease taken over by someone else stops the local listener.
+func (u *listenUseCase) runCoordinator(ctx context.Context) {
+	ticker := time.NewTicker(u.config.ClusterHeartbeatInterval)
+	defer ticker.Stop()
+	nudges := u.coord.Nudges(ctx)
+
+	u.heartbeat(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-nudges:
+			if !ok {
+				nudges = nil
+				continue
+			}
+			u.requestReconcile()
+		case <-ticker.C:
+			u.heartbeat(ctx)
+		}
+	}
+}
+
+func (u *listenUseCase) heartbeat(ctx context.Context) {
+	if err := u.coord.Heartbeat(ctx); err != nil {
+		log.Printf("Küme heartbeat gönderilemedi: %v", err)
+		return
+	}
+
+	members, err := u.coord.Members(ctx)
+	if err != nil {
+		log.Printf("Küme üyeleri alınamadı: %v", err)
+	} else if u.cluster.update(u.config.ClusterRingReplicas, members) {
+		log.Printf("Küme üyeleri değişti (%d sunucu), sahiplik yeniden dağıtılıyor", len(members))
+		u.requestReconcile()
+	}
+
+	running := ListenerManager.Snapshot()
+	streamers := make([]string, 0, len(running))
+	for streamer, info := range running {
+		if !info.Stopped() {
+			streamers = append(streamers, streamer)
+		}
+	}
+	lost, err := u.coord.Renew(ctx, streamers)
+	if err != nil {
+		// Nobody else can take a lease while Redis is unreachable, keep listening
+		log.Printf("Sahiplikler yenilenemedi: %v", err)
+		return
+	}
+	for _, streamer := range lost {
+		log.Printf("'%s' için sahiplik başka bir sunucuya geçti, dinleyici durduruluyor", streamer)
+		running[streamer].Stop()
+	}
+}
+
+// requestReconcile asks the reconciler loop for an early pass, never blocks
+func (u *listenUseCase) requestReconcile() {
+	select {
+	case u.reconcileNow <- struct{}{}:
+	default:
+	}
+}
+
+// Shutdown stops the background loops, hands this replica's streamers to the
+// others and waits up to ctx for the listeners to close and the buffered
+// events to be written
+func (u *listenUseCase) Shutdown(ctx context.Context) {
+	if u.cancel != nil {
+		u.cancel()
+	}
+
+	running := ListenerManager.Snapshot()
+	for _, info := range running {
+		info.Stop()
+	}
+
+	if u.coord != nil {
+		if err := u.coord.Leave(ctx); err != nil {
+			log.Printf("Kümeden ayrılınamadı: %v", err)
+		}
+		defer u.notifyCluster(ctx)
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+wait:
+	for len(ListenerManager.Snapshot()) > 0 {
+		select {
+		case <-ctx.Done():
+			log.Printf("Dinleyiciler kapanmadan çıkılıyor: %v", ctx.Err())
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	u.drainPipeline(ctx)
+}
+
+// drainPipeline flushes the sink queues, then the writer the postgres sink fills
+func (u *listenUseCase) drainPipeline(ctx context.Context) {
+	for _, stage := range []struct {
+		name string
+		stop context.CancelFunc
+		done chan struct{}
+	}{
+		{"çıktılar", u.stopSinks, u.sinksDone},
+		{"mesaj yazıcı", u.stopWriter, u.writerDone},
+		{"bağlantı denemeleri", u.stopAttempts, u.attemptsDone},
+	} {
+		stage.stop()
+		select {
+		case <-stage.done:
+		case <-ctx.Done():
+			log.Printf("Kapanırken %s boşaltılamadı: %v", stage.name, ctx.Err())
+			return
+		}
+	}
+}
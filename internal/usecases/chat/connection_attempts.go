@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"kick-chat/domain"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+type ConnectionAttemptRepository interface {
+	InsertConnectionAttempts(ctx context.Context, attempts []domain.ConnectionAttempt) error
+}
+
+// AttemptLog batches reconnect records. A flapping shared socket reports one
+// attempt per listener on it, writing each on its own would be a burst of inserts.
+// Record never blocks: when the buffer is full the attempt is only logged.
+type AttemptLog struct {
+	repo          ConnectionAttemptRepository
+	flushInterval time.Duration
+	queue         chan domain.ConnectionAttempt
+
+	dropped atomic.Int64
+}
+
+func NewAttemptLog(repo ConnectionAttemptRepository, config *Config) *AttemptLog {
+	return &AttemptLog{
+		repo:          repo,
+		flushInterval: config.AttemptFlushInterval,
+		queue:         make(chan domain.ConnectionAttempt, config.AttemptQueueSize),
+	}
+}
+
+func (l *AttemptLog) Record(attempt domain.ConnectionAttempt) {
+	select {
+	case l.queue <- attempt:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Run writes whatever was recorded every AttemptFlushInterval, and once more on ctx cancellation
+func (l *AttemptLog) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.flush()
+		case <-ctx.Done():
+			l.flush()
+			return
+		}
+	}
+}
+
+func (l *AttemptLog) flush() {
+	if dropped := l.dropped.Swap(0); dropped > 0 {
+		log.Printf("Kuyruk dolu olduğu için %d bağlantı denemesi kaydedilmedi", dropped)
+	}
+
+	batch := make([]domain.ConnectionAttempt, 0, len(l.queue))
+	for len(batch) < cap(batch) {
+		batch = append(batch, <-l.queue)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := l.repo.InsertConnectionAttempts(ctx, batch); err != nil {
+		log.Printf("%d bağlantı denemesi kaydedilemedi: %v", len(batch), err)
+	}
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Pusher event names sent by Kick on the chatrooms.%d.v2 channel
+const (
+	KickChatMessageEvent          = "App\\Events\\ChatMessageEvent"
+	KickMessageDeletedEvent       = "App\\Events\\MessageDeletedEvent"
+	KickUserBannedEvent           = "App\\Events\\UserBannedEvent"
+	KickUserUnbannedEvent         = "App\\Events\\UserUnbannedEvent"
+	KickPinnedMessageCreatedEvent = "App\\Events\\PinnedMessageCreatedEvent"
+	KickPinnedMessageDeletedEvent = "App\\Events\\PinnedMessageDeletedEvent"
+	KickSubscriptionEvent         = "App\\Events\\SubscriptionEvent"
+	KickGiftedSubscriptionsEvent  = "App\\Events\\GiftedSubscriptionsEvent"
+	KickStreamHostEvent           = "App\\Events\\StreamHostEvent"
+	KickPollUpdateEvent           = "App\\Events\\PollUpdateEvent"
+	KickPollDeleteEvent           = "App\\Events\\PollDeleteEvent"
+	KickChatroomUpdatedEvent      = "App\\Events\\ChatroomUpdatedEvent"
+	KickChatroomClearEvent        = "App\\Events\\ChatroomClearEvent"
+)
+
+// EventType is the short, storage friendly name of a decoded chat event
+type EventType string
+
+const (
+	EventTypeMessage              EventType = "message"
+	EventTypeMessageDeleted       EventType = "message_deleted"
+	EventTypeUserBanned           EventType = "user_banned"
+	EventTypeUserUnbanned         EventType = "user_unbanned"
+	EventTypePinnedMessageCreated EventType = "pinned_message_created"
+	EventTypePinnedMessageDeleted EventType = "pinned_message_deleted"
+	EventTypeSubscription         EventType = "subscription"
+	EventTypeGiftedSubscriptions  EventType = "gifted_subscriptions"
+	EventTypeStreamHost           EventType = "stream_host"
+	EventTypePollUpdate           EventType = "poll_update"
+	EventTypePollDelete           EventType = "poll_delete"
+	EventTypeChatroomUpdated      EventType = "chatroom_updated"
+	EventTypeChatroomClear        EventType = "chatroom_clear"
+)
+
+// ChatEvent is implemented by every typed Kick chatroom event
+type ChatEvent interface {
+	EventType() EventType
+}
+
+type KickUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Slug     string `json:"slug"`
+}
+
+type MessageMetadata struct {
+	OriginalSender  KickUser `json:"original_sender"`
+	OriginalMessage struct {
+		ID      string `json:"id"`
+		Content string `json:"content"`
+	} `json:"original_message"`
+}
+
+type MessageDeleted struct {
+	ID      string `json:"id"`
+	Message struct {
+		ID string `json:"id"`
+	} `json:"message"`
+	AIModerated bool `json:"aiModerated"`
+}
+
+type UserBanned struct {
+	ID        string     `json:"id"`
+	User      KickUser   `json:"user"`
+	BannedBy  KickUser   `json:"banned_by"`
+	Permanent bool       `json:"permanent"`
+	Duration  int        `json:"duration"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type UserUnbanned struct {
+	ID         string   `json:"id"`
+	User       KickUser `json:"user"`
+	UnbannedBy KickUser `json:"unbanned_by"`
+	Permanent  bool     `json:"permanent"`
+}
+
+type PinnedMessageCreated struct {
+	Message  Data     `json:"message"`
+	Duration string   `json:"duration"`
+	PinnedBy KickUser `json:"pinnedBy"`
+}
+
+type PinnedMessageDeleted struct{}
+
+type Subscription struct {
+	ChatroomID int    `json:"chatroom_id"`
+	Username   string `json:"username"`
+	Months     int    `json:"months"`
+}
+
+type GiftedSubscriptions struct {
+	ChatroomID      int      `json:"chatroom_id"`
+	GiftedUsernames []string `json:"gifted_usernames"`
+	GifterUsername  string   `json:"gifter_username"`
+	GifterTotal     int      `json:"gifter_total"`
+}
+
+// StreamHost covers both hosts and raids, Kick sends the same event for both
+type StreamHost struct {
+	ChatroomID      int    `json:"chatroom_id"`
+	OptionalMessage string `json:"optional_message"`
+	NumberViewers   int    `json:"number_viewers"`
+	HostUsername    string `json:"host_username"`
+}
+
+type PollOption struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+	Votes int    `json:"votes"`
+}
+
+type PollUpdate struct {
+	Poll struct {
+		Title                 string       `json:"title"`
+		Options               []PollOption `json:"options"`
+		Duration              int          `json:"duration"`
+		Remaining             int          `json:"remaining"`
+		ResultDisplayDuration int          `json:"result_display_duration"`
+	} `json:"poll"`
+}
+
+type PollDelete struct{}
+
+type ChatroomMode struct {
+	Enabled         bool `json:"enabled"`
+	MessageInterval int  `json:"message_interval,omitempty"`
+	MinDuration     int  `json:"min_duration,omitempty"`
+}
+
+type ChatroomUpdated struct {
+	ID                    int          `json:"id"`
+	SlowMode              ChatroomMode `json:"slow_mode"`
+	SubscribersMode       ChatroomMode `json:"subscribers_mode"`
+	FollowersMode         ChatroomMode `json:"followers_mode"`
+	EmotesMode            ChatroomMode `json:"emotes_mode"`
+	AdvancedBotProtection struct {
+		Enabled       bool `json:"enabled"`
+		RemainingTime int  `json:"remaining_time"`
+	} `json:"advanced_bot_protection"`
+}
+
+type ChatroomClear struct {
+	ID string `json:"id"`
+}
+
+func (Data) EventType() EventType                 { return EventTypeMessage }
+func (MessageDeleted) EventType() EventType       { return EventTypeMessageDeleted }
+func (UserBanned) EventType() EventType           { return EventTypeUserBanned }
+func (UserUnbanned) EventType() EventType         { return EventTypeUserUnbanned }
+func (PinnedMessageCreated) EventType() EventType { return EventTypePinnedMessageCreated }
+func (PinnedMessageDeleted) EventType() EventType { return EventTypePinnedMessageDeleted }
+func (Subscription) EventType() EventType         { return EventTypeSubscription }
+func (GiftedSubscriptions) EventType() EventType  { return EventTypeGiftedSubscriptions }
+func (StreamHost) EventType() EventType           { return EventTypeStreamHost }
+func (PollUpdate) EventType() EventType           { return EventTypePollUpdate }
+func (PollDelete) EventType() EventType           { return EventTypePollDelete }
+func (ChatroomUpdated) EventType() EventType      { return EventTypeChatroomUpdated }
+func (ChatroomClear) EventType() EventType        { return EventTypeChatroomClear }
+
+// eventFactories maps a Pusher event name to a constructor for its typed payload
+var eventFactories = map[string]func() ChatEvent{
+	KickChatMessageEvent:          func() ChatEvent { return &Data{} },
+	KickMessageDeletedEvent:       func() ChatEvent { return &MessageDeleted{} },
+	KickUserBannedEvent:           func() ChatEvent { return &UserBanned{} },
+	KickUserUnbannedEvent:         func() ChatEvent { return &UserUnbanned{} },
+	KickPinnedMessageCreatedEvent: func() ChatEvent { return &PinnedMessageCreated{} },
+	KickPinnedMessageDeletedEvent: func() ChatEvent { return &PinnedMessageDeleted{} },
+	KickSubscriptionEvent:         func() ChatEvent { return &Subscription{} },
+	KickGiftedSubscriptionsEvent:  func() ChatEvent { return &GiftedSubscriptions{} },
+	KickStreamHostEvent:           func() ChatEvent { return &StreamHost{} },
+	KickPollUpdateEvent:           func() ChatEvent { return &PollUpdate{} },
+	KickPollDeleteEvent:           func() ChatEvent { return &PollDelete{} },
+	KickChatroomUpdatedEvent:      func() ChatEvent { return &ChatroomUpdated{} },
+	KickChatroomClearEvent:        func() ChatEvent { return &ChatroomClear{} },
+}
+
+// DecodeEvent turns a raw Pusher envelope into a typed chat event.
+// ok is false for events we don't know about (pusher:* control frames etc).
+func DecodeEvent(msg Message) (event ChatEvent, ok bool, err error) {
+	factory, known := eventFactories[msg.Event]
+	if !known {
+		return nil, false, nil
+	}
+
+	// Kick double encodes the payload: data is a JSON string holding JSON
+	var raw string
+	if err := json.Unmarshal(msg.Data, &raw); err != nil {
+		return nil, true, fmt.Errorf("%s data string çözülemedi: %w", msg.Event, err)
+	}
+
+	event = factory()
+	if raw == "" || raw == "[]" || raw == "{}" {
+		return event, true, nil
+	}
+	if err := json.Unmarshal([]byte(raw), event); err != nil {
+		return nil, true, fmt.Errorf("%s payload çözülemedi: %w", msg.Event, err)
+	}
+	return event, true, nil
+}
+
+// eventID returns the Kick side identifier of an event when it has one
+func eventID(event ChatEvent) string {
+	switch e := event.(type) {
+	case *Data:
+		return e.ID
+	case *MessageDeleted:
+		return e.Message.ID
+	case *UserBanned:
+		return e.ID
+	case *UserUnbanned:
+		return e.ID
+	case *PinnedMessageCreated:
+		return e.Message.ID
+	case *ChatroomClear:
+		return e.ID
+	}
+	return ""
+}
+
+// describeEvent renders a one line human readable summary for console output
+func describeEvent(event ChatEvent) string {
+	switch e := event.(type) {
+	case *MessageDeleted:
+		return fmt.Sprintf("mesaj silindi (%s)", e.Message.ID)
+	case *UserBanned:
+		if e.Permanent {
+			return fmt.Sprintf("%s, %s tarafından kalıcı banlandı", e.User.Username, e.BannedBy.Username)
+		}
+		return fmt.Sprintf("%s, %s tarafından %d dk susturuldu", e.User.Username, e.BannedBy.Username, e.Duration)
+	case *UserUnbanned:
+		return fmt.Sprintf("%s banı %s tarafından kaldırıldı", e.User.Username, e.UnbannedBy.Username)
+	case *PinnedMessageCreated:
+		return fmt.Sprintf("%s mesajı sabitlendi: %s", e.Message.Sender.Username, e.Message.Content)
+	case *PinnedMessageDeleted:
+		return "sabitlenmiş mesaj kaldırıldı"
+	case *Subscription:
+		return fmt.Sprintf("%s abone oldu (%d ay)", e.Username, e.Months)
+	case *GiftedSubscriptions:
+		return fmt.Sprintf("%s %d abonelik hediye etti", e.GifterUsername, len(e.GiftedUsernames))
+	case *StreamHost:
+		return fmt.Sprintf("%s %d izleyiciyle host etti", e.HostUsername, e.NumberViewers)
+	case *PollUpdate:
+		return fmt.Sprintf("anket güncellendi: %s", e.Poll.Title)
+	case *PollDelete:
+		return "anket silindi"
+	case *ChatroomUpdated:
+		return fmt.Sprintf("sohbet ayarları değişti (slow=%t, sub=%t, follower=%t, emote=%t)",
+			e.SlowMode.Enabled, e.SubscribersMode.Enabled, e.FollowersMode.Enabled, e.EmotesMode.Enabled)
+	case *ChatroomClear:
+		return "sohbet temizlendi"
+	}
+	return string(event.EventType())
+}
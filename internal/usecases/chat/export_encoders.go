@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"kick-chat/domain"
+	"kick-chat/utils"
+	"strings"
+	"time"
+)
+
+type csvEncoder struct {
+	w       *csv.Writer
+	started bool
+}
+
+func newCSVEncoder(w io.Writer, _ exportMeta) messageEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+var csvExportHeader = []string{"timestamp", "streamer", "sender", "sender_color", "content", "links", "kick_message_id"}
+
+func (e *csvEncoder) encode(msg domain.StoredMessage) error {
+	if !e.started {
+		e.started = true
+		if err := e.w.Write(csvExportHeader); err != nil {
+			return err
+		}
+	}
+	return e.w.Write([]string{
+		msg.Timestamp.UTC().Format(time.RFC3339Nano),
+		csvCell(msg.Streamer),
+		csvCell(msg.SenderUsername),
+		csvCell(msg.SenderColor),
+		csvCell(msg.Content),
+		csvCell(strings.Join(msg.ExtractedLinks, " ")),
+		csvCell(msg.KickMessageID),
+	})
+}
+
+// csvCell defuses chat text a spreadsheet would run as a formula ("=HYPERLINK(...)")
+// by prefixing it with a quote, which Excel and Sheets show as plain text
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *csvEncoder) close() error {
+	if !e.started {
+		e.w.Write(csvExportHeader)
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func newJSONLEncoder(w io.Writer, _ exportMeta) messageEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlEncoder{enc: enc}
+}
+
+func (e *jsonlEncoder) encode(msg domain.StoredMessage) error {
+	return e.enc.Encode(msg)
+}
+
+func (e *jsonlEncoder) close() error {
+	return nil
+}
+
+const htmlTranscriptHead = `<!DOCTYPE html>
+<html lang="tr">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body{margin:0;background:#0b0e0f;color:#e8e8e8;font:14px/1.5 system-ui,-apple-system,"Segoe UI",sans-serif}
+header{position:sticky;top:0;padding:12px 16px;background:#151a1c;border-bottom:1px solid #24292b}
+header h1{margin:0;font-size:18px}
+header p{margin:2px 0 0;color:#8a9396;font-size:12px}
+main{padding:8px 16px}
+.d{margin:16px 0 6px;color:#53fc18;font-weight:600;font-size:12px;text-transform:uppercase}
+.m{padding:2px 0;word-wrap:break-word}
+.m time{color:#6b7477;font-size:12px;margin-right:6px;font-variant-numeric:tabular-nums}
+.m .u{font-weight:600}
+.m a{color:#53fc18}
+footer{padding:12px 16px;color:#6b7477;font-size:12px}
+</style>
+</head>
+<body>
+<header><h1>%s</h1><p>%s</p></header>
+<main>
+`
+
+const htmlDefaultSenderColor = "#e8e8e8"
+
+// htmlEncoder writes a self-contained transcript, no scripts or external assets.
+// Sender colors use the same hex parsing as the console renderer.
+type htmlEncoder struct {
+	w       io.Writer
+	meta    exportMeta
+	started bool
+	lastDay string
+	count   int
+}
+
+func newHTMLEncoder(w io.Writer, meta exportMeta) messageEncoder {
+	return &htmlEncoder{w: w, meta: meta}
+}
+
+// header is written with the first message, a listener export only learns its streamer there
+func (e *htmlEncoder) header(streamer string) error {
+	e.started = true
+	if streamer == "" {
+		streamer = "kick"
+	}
+	title := html.EscapeString(streamer + " sohbet kaydı")
+
+	info := "Oluşturulma: " + e.meta.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")
+	if e.meta.From != nil || e.meta.To != nil {
+		from, to := "…", "…"
+		if e.meta.From != nil {
+			from = e.meta.From.UTC().Format("2006-01-02 15:04")
+		}
+		if e.meta.To != nil {
+			to = e.meta.To.UTC().Format("2006-01-02 15:04")
+		}
+		info = fmt.Sprintf("%s – %s UTC · %s", from, to, info)
+	}
+	_, err := fmt.Fprintf(e.w, htmlTranscriptHead, title, title, html.EscapeString(info))
+	return err
+}
+
+func (e *htmlEncoder) encode(msg domain.StoredMessage) error {
+	if !e.started {
+		if err := e.header(msg.Streamer); err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	ts := msg.Timestamp.UTC()
+	if day := ts.Format("2006-01-02"); day != e.lastDay {
+		e.lastDay = day
+		if _, err := fmt.Fprintf(e.w, "<div class=\"d\">%s</div>\n", day); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(e.w, "<div class=\"m\"><time datetime=\"%s\">%s</time><span class=\"u\" style=\"color:%s\">%s</span>: %s</div>\n",
+		ts.Format(time.RFC3339), ts.Format("15:04:05"),
+		utils.GetCSSColorFromHex(msg.SenderColor, htmlDefaultSenderColor),
+		html.EscapeString(msg.SenderUsername),
+		linkifyHTML(msg.Content))
+	return err
+}
+
+func (e *htmlEncoder) close() error {
+	if !e.started {
+		if err := e.header(e.meta.Streamer); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(e.w, "</main>\n<footer>%d mesaj</footer>\n</body>\n</html>\n", e.count)
+	return err
+}
+
+// linkifyHTML escapes content and turns the links the listener extracts into anchors
+func linkifyHTML(content string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range linkRegex.FindAllStringIndex(content, -1) {
+		sb.WriteString(html.EscapeString(content[last:loc[0]]))
+		link := html.EscapeString(content[loc[0]:loc[1]])
+		fmt.Fprintf(&sb, `<a href="%s" rel="noopener noreferrer nofollow">%s</a>`, link, link)
+		last = loc[1]
+	}
+	sb.WriteString(html.EscapeString(content[last:]))
+	return sb.String()
+}
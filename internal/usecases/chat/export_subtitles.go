@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"kick-chat/domain"
+	"kick-chat/utils"
+	"strings"
+	"time"
+)
+
+const (
+	SenderFormatColor = "color"
+	SenderFormatPlain = "plain"
+	SenderFormatNone  = "none"
+)
+
+// SubtitleOptions shape vtt and srt exports. Cue times are offsets from Start,
+// the moment the broadcast (and so the recording) began.
+type SubtitleOptions struct {
+	Start        *time.Time
+	CueDuration  time.Duration
+	MaxLines     int
+	SenderFormat string
+}
+
+type subtitleLine struct {
+	text    string
+	expires time.Duration
+}
+
+// subtitleEncoder renders chat as a rolling overlay: a message shows for CueDuration,
+// at most MaxLines at once with the newest at the bottom. Every change of the visible
+// lines starts a new cue, so cues never overlap and players need no layout support.
+type subtitleEncoder struct {
+	w       io.Writer
+	opts    SubtitleOptions
+	srt     bool
+	started bool
+	seq     int
+
+	visible []subtitleLine
+	since   time.Duration
+}
+
+func newVTTEncoder(w io.Writer, meta exportMeta) messageEncoder {
+	return &subtitleEncoder{w: w, opts: meta.Subtitles}
+}
+
+func newSRTEncoder(w io.Writer, meta exportMeta) messageEncoder {
+	return &subtitleEncoder{w: w, opts: meta.Subtitles, srt: true}
+}
+
+func (e *subtitleEncoder) encode(msg domain.StoredMessage) error {
+	at := msg.Timestamp.Sub(*e.opts.Start)
+	if at < 0 {
+		return nil // sent before the broadcast started
+	}
+	if err := e.advance(at); err != nil {
+		return err
+	}
+
+	e.visible = append(e.visible, subtitleLine{text: e.line(msg), expires: at + e.opts.CueDuration})
+	if len(e.visible) > e.opts.MaxLines {
+		e.visible = e.visible[len(e.visible)-e.opts.MaxLines:]
+	}
+	return nil
+}
+
+// advance emits cues for the visible lines up to at, dropping lines as they expire
+func (e *subtitleEncoder) advance(at time.Duration) error {
+	for len(e.visible) > 0 {
+		end := at
+		if first := e.visible[0].expires; first < end {
+			end = first
+		}
+		if err := e.cue(e.since, end); err != nil {
+			return err
+		}
+		e.since = end
+		// Lines expire in the order they were added
+		for len(e.visible) > 0 && e.visible[0].expires <= end {
+			e.visible = e.visible[1:]
+		}
+		if end == at {
+			break
+		}
+	}
+	e.since = at
+	return nil
+}
+
+func (e *subtitleEncoder) cue(start, end time.Duration) error {
+	if err := e.header(); err != nil {
+		return err
+	}
+	if end <= start || len(e.visible) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(e.visible))
+	for i, line := range e.visible {
+		lines[i] = line.text
+	}
+	e.seq++
+	var err error
+	if e.srt {
+		_, err = fmt.Fprintf(e.w, "%d\n%s --> %s\n%s\n\n", e.seq, formatCueTime(start, ','), formatCueTime(end, ','), strings.Join(lines, "\n"))
+	} else {
+		_, err = fmt.Fprintf(e.w, "%s --> %s\n%s\n\n", formatCueTime(start, '.'), formatCueTime(end, '.'), strings.Join(lines, "\n"))
+	}
+	return err
+}
+
+func (e *subtitleEncoder) header() error {
+	if e.started || e.srt {
+		return nil
+	}
+	e.started = true
+	_, err := fmt.Fprintf(e.w, "WEBVTT\n\nNOTE kick-chat, başlangıç %s\n\n", e.opts.Start.UTC().Format(time.RFC3339))
+	return err
+}
+
+func (e *subtitleEncoder) close() error {
+	if len(e.visible) > 0 {
+		if err := e.advance(e.visible[len(e.visible)-1].expires); err != nil {
+			return err
+		}
+	}
+	return e.header()
+}
+
+// cueEscaper leaves quotes alone, not every SRT player decodes numeric references
+var cueEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// line escapes the message for the cue text, both formats treat < and & as markup
+func (e *subtitleEncoder) line(msg domain.StoredMessage) string {
+	content := cueEscaper.Replace(msg.Content)
+	sender := cueEscaper.Replace(msg.SenderUsername)
+
+	switch e.opts.SenderFormat {
+	case SenderFormatNone:
+		return content
+	case SenderFormatPlain:
+		return sender + ": " + content
+	}
+
+	// Colors follow the console: the sender's Kick color when valid. SRT players
+	// understand <font>, WebVTT only its predefined color classes.
+	color := utils.GetCSSColorFromHex(msg.SenderColor, "")
+	if e.srt {
+		if color == "" {
+			return "<b>" + sender + "</b>: " + content
+		}
+		return fmt.Sprintf(`<font color="%s"><b>%s</b></font>: %s`, color, sender, content)
+	}
+	if color == "" {
+		return "<b>" + sender + "</b>: " + content
+	}
+	return fmt.Sprintf("<c.%s><b>%s</b></c>: %s", nearestCueColor(color), sender, content)
+}
+
+// WebVTT's default color classes, a stylesheet would need every color before the first cue
+var cueColors = []struct {
+	name    string
+	r, g, b int
+}{
+	{"white", 255, 255, 255},
+	{"lime", 0, 255, 0},
+	{"cyan", 0, 255, 255},
+	{"red", 255, 0, 0},
+	{"yellow", 255, 255, 0},
+	{"magenta", 255, 0, 255},
+	{"blue", 0, 0, 255},
+}
+
+// nearestCueColor picks the closest class for a #rrggbb color; black is left out,
+// it would be unreadable on most players' backgrounds
+func nearestCueColor(css string) string {
+	var r, g, b int
+	if _, err := fmt.Sscanf(css, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return "white"
+	}
+	best, bestDist := "white", -1
+	for _, c := range cueColors {
+		dr, dg, db := r-c.r, g-c.g, b-c.b
+		if dist := dr*dr + dg*dg + db*db; bestDist < 0 || dist < bestDist {
+			best, bestDist = c.name, dist
+		}
+	}
+	return best
+}
+
+// formatCueTime writes HH:MM:SS.mmm, SRT uses a comma before the milliseconds
+func formatCueTime(d time.Duration, sep byte) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
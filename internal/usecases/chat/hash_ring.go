@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// HashRing maps streamers onto instances with virtual nodes, so adding or losing
+// an instance only moves the streamers adjacent to it on the ring
+type HashRing struct {
+	replicas int
+	hashes   []uint32
+	owners   map[uint32]string
+	members  []string
+}
+
+func NewHashRing(replicas int, members []string) *HashRing {
+	r := &HashRing{
+		replicas: replicas,
+		owners:   make(map[uint32]string, replicas*len(members)),
+		members:  append([]string(nil), members...),
+	}
+	sort.Strings(r.members)
+	for _, member := range r.members {
+		for i := 0; i < replicas; i++ {
+			h := hashKey(member + "#" + strconv.Itoa(i))
+			r.hashes = append(r.hashes, h)
+			r.owners[h] = member
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// Owner returns the member responsible for key, or "" for an empty ring
+func (r *HashRing) Owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := hashKey(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
+
+// SameMembers reports whether the ring was built from exactly these members
+func (r *HashRing) SameMembers(members []string) bool {
+	if len(members) != len(r.members) {
+		return false
+	}
+	sorted := append([]string(nil), members...)
+	sort.Strings(sorted)
+	for i := range sorted {
+		if sorted[i] != r.members[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -4,40 +4,177 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"kick-chat/domain"
 	"kick-chat/internal/middleware"
-	"kick-chat/utils"
 	"log"
-	"net/http"
 	"regexp"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
-	"github.com/logrusorgru/aurora"
 )
 
 // Configuration
 type Config struct {
-	WebSocketUrl             string
-	ChatroomSubscribeCommand string
-	BatchSize                int
-	MessageBufferSize        int
-	ReconnectInterval        time.Duration
-	MaxReconnectAttempts     int
+	WebSocketUrl               string
+	ChatroomSubscribeCommand   string
+	ChatroomUnsubscribeCommand string
+	ChannelsPerConnection      int
+	BatchSize                  int
+	FlushInterval              time.Duration
+	WriteQueueSize             int
+	WriteMaxRetries            int
+	LiveBacklogSize            int
+	LiveClientBufferSize       int
+	LiveResumeLimit            int
+	LiveReplaySize             int
+	LiveReplayTTL              time.Duration
+	FanoutQueueSize            int
+	SinkBufferSize             int
+	SinkWriteTimeout           time.Duration
+	AlertRulesRefresh          time.Duration
+	AlertMaxRulesPerUser       int
+	AlertMaxPatternLength      int
+	AlertDefaultCooldown       int
+	AlertMaxCooldown           int
+	AlertDefaultLimit          int
+	AlertMaxLimit              int
+	WebhookRefresh             time.Duration
+	WebhookPollInterval        time.Duration
+	WebhookWorkers             int
+	WebhookClaimLimit          int
+	WebhookTimeout             time.Duration
+	WebhookMaxAttempts         int
+	WebhookRetryBase           time.Duration
+	WebhookRetryMax            time.Duration
+	WebhookMaxEndpointsPerUser int
+	WebhookDefaultRateLimit    int
+	WebhookMaxRateLimit        int
+	WebhookDefaultLimit        int
+	WebhookMaxLimit            int
+	WebhookBatchWindow         time.Duration
+	WebhookProfileCacheTTL     time.Duration
+	SubtitleCueDuration        time.Duration
+	SubtitleMaxCueDuration     time.Duration
+	SubtitleMaxLines           int
+	SubtitleMaxLinesLimit      int
+	BusBatchSize               int
+	BusFlushInterval           time.Duration
+	BusQueueSize               int
+	OutboxRetryInterval        time.Duration
+	OutboxDrainLimit           int
+	OutboxMaxAttempts          int
+	HistoryDefaultLimit        int
+	HistoryMaxLimit            int
+	SearchDefaultLimit         int
+	SearchMaxLimit             int
+	SearchFacetSize            int
+	ChannelCacheTTL            time.Duration
+	ResolverTimeout            time.Duration
+	ResolverFailureThreshold   int
+	ResolverOpenDuration       time.Duration
+	ListenerIdleAfter          time.Duration
+	ReconcileInterval          time.Duration
+	FailedRetryAfter           time.Duration
+	ClusterHeartbeatInterval   time.Duration
+	ClusterInstanceTTL         time.Duration
+	ClusterLeaseTTL            time.Duration
+	ClusterRingReplicas        int
+	DefaultListenDuration      time.Duration
+	MinListenDuration          time.Duration
+	MaxListenDuration          time.Duration
+	UserListenQuota            time.Duration
+	MessageBufferSize          int
+	ReconnectBaseDelay         time.Duration
+	ReconnectMaxDelay          time.Duration
+	ReconnectJitter            float64
+	StableConnectionAfter      time.Duration
+	AttemptFlushInterval       time.Duration
+	AttemptQueueSize           int
+	PongTimeout                time.Duration
 }
 
 var AppConfig = &Config{
-	BatchSize:                10,
-	ChatroomSubscribeCommand: "{\"event\":\"pusher:subscribe\",\"data\":{\"auth\":\"\",\"channel\":\"chatrooms.%d.v2\"}}",
-	WebSocketUrl:             "wss://ws-us2.pusher.com/app/32cbd69e4b950bf97679?protocol=7&client=js&version=8.4.0&flash=false",
-	MessageBufferSize:        1000,
-	ReconnectInterval:        5 * time.Second,
-	MaxReconnectAttempts:     3,
+	BatchSize:                  10,
+	FlushInterval:              2 * time.Second,
+	WriteQueueSize:             10000,
+	WriteMaxRetries:            3,
+	LiveBacklogSize:            50,
+	LiveClientBufferSize:       256,
+	LiveResumeLimit:            1000,
+	LiveReplaySize:             200,
+	LiveReplayTTL:              1 * time.Hour,
+	FanoutQueueSize:            10000,
+	SinkBufferSize:             1000,
+	SinkWriteTimeout:           10 * time.Second,
+	AlertRulesRefresh:          1 * time.Minute,
+	AlertMaxRulesPerUser:       50,
+	AlertMaxPatternLength:      200,
+	AlertDefaultCooldown:       60,
+	AlertMaxCooldown:           86400,
+	AlertDefaultLimit:          50,
+	AlertMaxLimit:              200,
+	WebhookRefresh:             1 * time.Minute,
+	WebhookPollInterval:        2 * time.Second,
+	WebhookWorkers:             4,
+	WebhookClaimLimit:          50,
+	WebhookTimeout:             10 * time.Second,
+	WebhookMaxAttempts:         8,
+	WebhookRetryBase:           10 * time.Second,
+	WebhookRetryMax:            1 * time.Hour,
+	WebhookMaxEndpointsPerUser: 10,
+	WebhookDefaultRateLimit:    60,
+	WebhookMaxRateLimit:        600,
+	WebhookDefaultLimit:        50,
+	WebhookMaxLimit:            200,
+	WebhookBatchWindow:         2 * time.Second,
+	WebhookProfileCacheTTL:     10 * time.Minute,
+	SubtitleCueDuration:        5 * time.Second,
+	SubtitleMaxCueDuration:     1 * time.Minute,
+	SubtitleMaxLines:           3,
+	SubtitleMaxLinesLimit:      10,
+	BusBatchSize:               100,
+	BusFlushInterval:           1 * time.Second,
+	BusQueueSize:               10000,
+	OutboxRetryInterval:        5 * time.Second,
+	OutboxDrainLimit:           500,
+	OutboxMaxAttempts:          5,
+	HistoryDefaultLimit:        50,
+	HistoryMaxLimit:            200,
+	SearchDefaultLimit:         20,
+	SearchMaxLimit:             100,
+	SearchFacetSize:            10,
+	ChannelCacheTTL:            6 * time.Hour,
+	ResolverTimeout:            10 * time.Second,
+	ResolverFailureThreshold:   5,
+	ResolverOpenDuration:       1 * time.Minute,
+	ListenerIdleAfter:          5 * time.Minute,
+	ReconcileInterval:          30 * time.Second,
+	FailedRetryAfter:           10 * time.Minute,
+	ClusterHeartbeatInterval:   10 * time.Second,
+	ClusterInstanceTTL:         30 * time.Second,
+	ClusterLeaseTTL:            30 * time.Second,
+	ClusterRingReplicas:        64,
+	DefaultListenDuration:      5 * time.Hour,
+	MinListenDuration:          1 * time.Minute,
+	MaxListenDuration:          24 * time.Hour,
+	UserListenQuota:            72 * time.Hour,
+	ChatroomSubscribeCommand:   "{\"event\":\"pusher:subscribe\",\"data\":{\"auth\":\"\",\"channel\":\"%s\"}}",
+	ChatroomUnsubscribeCommand: "{\"event\":\"pusher:unsubscribe\",\"data\":{\"channel\":\"%s\"}}",
+	ChannelsPerConnection:      100,
+	WebSocketUrl:               "wss://ws-us2.pusher.com/app/32cbd69e4b950bf97679?protocol=7&client=js&version=8.4.0&flash=false",
+	MessageBufferSize:          1000,
+	ReconnectBaseDelay:         1 * time.Second,
+	ReconnectMaxDelay:          2 * time.Minute,
+	ReconnectJitter:            0.3,
+	StableConnectionAfter:      1 * time.Minute,
+	AttemptFlushInterval:       5 * time.Second,
+	AttemptQueueSize:           1000,
+	PongTimeout:                30 * time.Second,
 }
 
 // Link regex compiled once
@@ -45,8 +182,9 @@ var linkRegex = regexp.MustCompile(`https?://[^\s]+`)
 
 // Domain Models
 type Message struct {
-	Event string          `json:"event"`
-	Data  json.RawMessage `json:"data"`
+	Event   string          `json:"event"`
+	Channel string          `json:"channel,omitempty"`
+	Data    json.RawMessage `json:"data"`
 }
 
 type Identity struct {
@@ -60,32 +198,22 @@ type Sender struct {
 }
 
 type Data struct {
-	Type      string    `json:"type"`
-	ID        string    `json:"id"`
-	Content   string    `json:"content"`
-	Sender    Sender    `json:"sender"`
-	Timestamp time.Time `json:"timestamp"`
-}
-
-type KickUserInfo struct {
-	Chatroom ChatroomInfo `json:"chatroom"`
-	User     UserInfo     `json:"user"`
-}
-
-type UserInfo struct {
-	ID         int    `json:"id"`
-	ProfilePic string `json:"profile_pic"`
-}
-
-type ChatroomInfo struct {
-	ID int `json:"id"`
+	Type       string           `json:"type"`
+	ID         string           `json:"id"`
+	ChatroomID int              `json:"chatroom_id"`
+	Content    string           `json:"content"`
+	Sender     Sender           `json:"sender"`
+	Timestamp  time.Time        `json:"created_at"`
+	Metadata   *MessageMetadata `json:"metadata,omitempty"`
 }
 
 // Enhanced UserRequestInfo with validation
 type UserRequestInfo struct {
 	UserID      uuid.UUID `json:"user_id"`
+	ListenerID  uuid.UUID `json:"listener_id"`
 	RequestTime time.Time `json:"request_time"`
 	EndTime     time.Time `json:"end_time"`
+	Sinks       []string  `json:"sinks,omitempty"`
 }
 
 func (u *UserRequestInfo) IsActive() bool {
@@ -95,48 +223,262 @@ func (u *UserRequestInfo) IsActive() bool {
 // Enhanced ListenerInfo with better state management
 type ListenerInfo struct {
 	Username          string                        `json:"username"`
-	Client            *websocket.Conn               `json:"-"`
+	ChatroomID        int                           `json:"chatroom_id"`
 	UserRequests      map[uuid.UUID]UserRequestInfo `json:"user_requests"`
 	OverallEndTime    time.Time                     `json:"overall_end_time"`
-	IsGlobalActive    bool                          `json:"is_global_active"`
+	State             ListenerState                 `json:"state"`
+	StateChangedAt    time.Time                     `json:"state_changed_at"`
+	LastError         string                        `json:"last_error,omitempty"`
+	IsSubscribed      bool                          `json:"is_subscribed"`
 	ListenerDBID      uuid.UUID                     `json:"listener_db_id"`
-	DataChannel       chan Data                     `json:"-"`
+	EventChannel      chan ChatEvent                `json:"-"`
+	ErrorChannel      chan error                    `json:"-"`
 	StopChannel       chan struct{}                 `json:"-"`
 	ReconnectAttempts int                           `json:"reconnect_attempts"`
 	LastActivity      time.Time                     `json:"last_activity"`
+	LastEventAt       time.Time                     `json:"last_event_at"`
+
+	mu        sync.RWMutex
+	stopOnce  sync.Once
+	stateHook func(ListenerStateChange)
+	// owned is set from the moment a listening goroutine is launched until it has
+	// cleaned up, unlike the state it already holds while that goroutine is pending
+	owned atomic.Bool
+	// sinks is the union of the requests' sinks, nil when any request wants them all
+	sinks map[string]struct{}
+	// backoff belongs to the listening goroutine; a confirmed subscription starts
+	// its stable connection clock
+	backoff *Backoff
+}
+
+// IsActive reports whether a listening goroutine currently owns the listener
+func (l *ListenerInfo) IsActive() bool {
+	return l.CurrentState().Running()
+}
+
+// claim takes ownership for a new listening goroutine, false when one already has it
+func (l *ListenerInfo) claim() bool {
+	return l.owned.CompareAndSwap(false, true)
+}
+
+// SetSubscribed is driven by Pusher's subscription_succeeded / disconnects, not by our subscribe call
+func (l *ListenerInfo) SetSubscribed(subscribed bool) {
+	l.mu.Lock()
+	l.IsSubscribed = subscribed
+	if subscribed {
+		l.LastActivity = time.Now()
+	}
+	state := l.State
+	backoff := l.backoff
+	l.mu.Unlock()
+
+	if subscribed && backoff != nil {
+		backoff.MarkConnected()
+	}
+
+	switch {
+	case subscribed && (state == StateConnecting || state == StateReconnecting):
+		l.Transition(StateSubscribed, nil)
+	case !subscribed && (state == StateSubscribed || state == StatePaused):
+		l.Transition(StateReconnecting, nil)
+	}
+}
+
+// Touch records chat activity and wakes a paused listener
+func (l *ListenerInfo) Touch() {
+	l.mu.Lock()
+	l.LastEventAt = time.Now()
+	state := l.State
+	l.mu.Unlock()
+
+	if state == StatePaused {
+		l.Transition(StateSubscribed, nil)
+	}
+}
+
+// IdleSince returns how long a subscribed listener has gone without chat events
+func (l *ListenerInfo) IdleSince() time.Duration {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	last := l.LastEventAt
+	if l.StateChangedAt.After(last) {
+		last = l.StateChangedAt
+	}
+	return time.Since(last)
+}
+
+func (l *ListenerInfo) Subscribed() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.IsSubscribed
+}
+
+// NotifyError wakes the processing loop with a connection level failure without blocking the socket reader
+func (l *ListenerInfo) NotifyError(err error) {
+	select {
+	case l.ErrorChannel <- err:
+	default:
+	}
+}
+
+// RecordReconnect bumps the reconnect counter and returns the new value
+func (l *ListenerInfo) RecordReconnect() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ReconnectAttempts++
+	return l.ReconnectAttempts
+}
 
-	mu sync.RWMutex
+func (l *ListenerInfo) EndTime() time.Time {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.OverallEndTime
 }
 
-// Thread-safe methods for ListenerInfo
-func (l *ListenerInfo) SetActive(active bool) {
+func (l *ListenerInfo) AddUserRequest(userID, listenerID uuid.UUID, endTime time.Time, sinks []string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.IsGlobalActive = active
+
+	request, ok := l.UserRequests[userID]
+	if !ok {
+		request = UserRequestInfo{UserID: userID, RequestTime: time.Now()}
+	}
+	request.ListenerID = listenerID
+	request.EndTime = endTime
+	request.Sinks = sinks
+	l.UserRequests[userID] = request
+
+	l.recalculateLocked()
 	l.LastActivity = time.Now()
 }
 
-func (l *ListenerInfo) IsActive() bool {
+// SetUserRequestEnd moves an existing request's end time, which may also shorten the listener
+func (l *ListenerInfo) SetUserRequestEnd(userID uuid.UUID, endTime time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	request, ok := l.UserRequests[userID]
+	if !ok {
+		return false
+	}
+	request.EndTime = endTime
+	l.UserRequests[userID] = request
+	l.recalculateLocked()
+	return true
+}
+
+// recalculateLocked keeps OverallEndTime equal to the latest request end and
+// the sink set equal to what the requests together asked for
+func (l *ListenerInfo) recalculateLocked() {
+	if len(l.UserRequests) == 0 {
+		return
+	}
+	var latest time.Time
+	all := false
+	sinks := make(map[string]struct{})
+	for _, request := range l.UserRequests {
+		if request.EndTime.After(latest) {
+			latest = request.EndTime
+		}
+		if request.Sinks == nil {
+			all = true
+		}
+		for _, name := range request.Sinks {
+			sinks[name] = struct{}{}
+		}
+	}
+	if all {
+		sinks = nil
+	}
+	l.OverallEndTime = latest
+	l.sinks = sinks
+}
+
+// SinkSet returns the sinks events should go to, nil meaning all of them
+func (l *ListenerInfo) SinkSet() map[string]struct{} {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	return l.IsGlobalActive
+	return l.sinks
 }
 
-func (l *ListenerInfo) AddUserRequest(userID uuid.UUID, endTime time.Time) {
+// RemoveUserRequest drops userID's request and returns how many remain. When the
+// removed request owned ListenerDBID, messages move to a remaining user's row.
+func (l *ListenerInfo) RemoveUserRequest(userID uuid.UUID) (int, bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	l.UserRequests[userID] = UserRequestInfo{
-		UserID:      userID,
-		RequestTime: time.Now(),
-		EndTime:     endTime,
+	removed, ok := l.UserRequests[userID]
+	if !ok {
+		return len(l.UserRequests), false
 	}
+	delete(l.UserRequests, userID)
 
-	// Update overall end time if necessary
-	if endTime.After(l.OverallEndTime) {
-		l.OverallEndTime = endTime
+	if len(l.UserRequests) == 0 {
+		l.OverallEndTime = time.Now()
+		return 0, true
+	}
+
+	l.recalculateLocked()
+	if removed.ListenerID == l.ListenerDBID {
+		var latest UserRequestInfo
+		for _, request := range l.UserRequests {
+			if request.ListenerID != uuid.Nil && request.EndTime.After(latest.EndTime) {
+				latest = request
+			}
+		}
+		if latest.ListenerID != uuid.Nil {
+			l.ListenerDBID = latest.ListenerID
+		}
+	}
+	return len(l.UserRequests), true
+}
+
+// ListenerStatus is a consistent copy of the runtime state of a listener
+type ListenerStatus struct {
+	State             ListenerState
+	StateChangedAt    time.Time
+	LastError         string
+	LastEventAt       time.Time
+	Subscribed        bool
+	Stopped           bool
+	ChatroomID        int
+	ReconnectAttempts int
+	LastActivity      time.Time
+	OverallEndTime    time.Time
+	RequestCount      int
+}
+
+func (l *ListenerInfo) Status() ListenerStatus {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return ListenerStatus{
+		State:             l.State,
+		StateChangedAt:    l.StateChangedAt,
+		LastError:         l.LastError,
+		LastEventAt:       l.LastEventAt,
+		Subscribed:        l.IsSubscribed,
+		Stopped:           l.Stopped(),
+		ChatroomID:        l.ChatroomID,
+		ReconnectAttempts: l.ReconnectAttempts,
+		LastActivity:      l.LastActivity,
+		OverallEndTime:    l.OverallEndTime,
+		RequestCount:      len(l.UserRequests),
+	}
+}
+
+// Stop closes StopChannel once, however many callers race to stop the listener
+func (l *ListenerInfo) Stop() {
+	l.stopOnce.Do(func() { close(l.StopChannel) })
+}
+
+// Stopped reports whether Stop was called; a stopped listener is never restarted
+func (l *ListenerInfo) Stopped() bool {
+	select {
+	case <-l.StopChannel:
+		return true
+	default:
+		return false
 	}
-	l.LastActivity = time.Now()
 }
 
 func (l *ListenerInfo) RemoveExpiredRequests() {
@@ -164,6 +506,14 @@ func (l *ListenerInfo) HasActiveRequests() bool {
 	return false
 }
 
+// HasUserRequest reports whether userID has an unexpired request on this listener
+func (l *ListenerInfo) HasUserRequest(userID uuid.UUID) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	request, ok := l.UserRequests[userID]
+	return ok && time.Now().Before(request.EndTime)
+}
+
 func (l *ListenerInfo) GetActiveRequestCount() int {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
@@ -201,16 +551,51 @@ func (lm *ListenerManagerType) RemoveListener(username string) {
 	lm.mu.Lock()
 	defer lm.mu.Unlock()
 	if listener, exists := lm.listeners[username]; exists {
-		// Close channels safely
-		select {
-		case <-listener.StopChannel:
-		default:
-			close(listener.StopChannel)
-		}
+		listener.Stop()
 		delete(lm.listeners, username)
 	}
 }
 
+// Detach removes info only if it is still the registered listener for its
+// streamer, so a finishing goroutine can't evict the listener that replaced it
+func (lm *ListenerManagerType) Detach(info *ListenerInfo) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	if current, exists := lm.listeners[info.Username]; exists && current == info {
+		info.Stop()
+		delete(lm.listeners, info.Username)
+	}
+}
+
+// StreamersForUser returns streamers the user has an open in-memory request for
+func (lm *ListenerManagerType) StreamersForUser(userID uuid.UUID) []string {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	var streamers []string
+	for username, listener := range lm.listeners {
+		listener.mu.RLock()
+		request, ok := listener.UserRequests[userID]
+		listener.mu.RUnlock()
+		if ok && request.IsActive() {
+			streamers = append(streamers, username)
+		}
+	}
+	return streamers
+}
+
+// StateCounts groups in-memory listeners by state
+func (lm *ListenerManagerType) StateCounts() map[ListenerState]int {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	counts := make(map[ListenerState]int)
+	for _, listener := range lm.listeners {
+		counts[listener.CurrentState()]++
+	}
+	return counts
+}
+
 func (lm *ListenerManagerType) GetActiveListenerCount() int {
 	lm.mu.RLock()
 	defer lm.mu.RUnlock()
@@ -224,42 +609,16 @@ func (lm *ListenerManagerType) GetActiveListenerCount() int {
 	return count
 }
 
-// Cleanup expired listeners periodically
-func (lm *ListenerManagerType) StartCleanupRoutine(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			lm.cleanupExpiredListeners()
-		}
-	}
-}
-
-func (lm *ListenerManagerType) cleanupExpiredListeners() {
-	lm.mu.Lock()
-	defer lm.mu.Unlock()
+// Snapshot copies the registry so callers can iterate without holding the lock
+func (lm *ListenerManagerType) Snapshot() map[string]*ListenerInfo {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
 
-	now := time.Now()
-	fmt.Println("now:", now)
+	listeners := make(map[string]*ListenerInfo, len(lm.listeners))
 	for username, listener := range lm.listeners {
-		// Remove expired requests first
-		listener.RemoveExpiredRequests()
-
-		// If no active requests and listener is inactive, remove it
-		if !listener.HasActiveRequests() && !listener.IsActive() {
-			log.Printf("Cleaning up expired listener for '%s'", username)
-			select {
-			case <-listener.StopChannel:
-			default:
-				close(listener.StopChannel)
-			}
-			delete(lm.listeners, username)
-		}
+		listeners[username] = listener
 	}
+	return listeners
 }
 
 // Global ListenerManager
@@ -273,7 +632,6 @@ func NewListenerManager() *ListenerManagerType {
 
 // Repository interface remains the same
 type ListenPostgresRepository interface {
-	InsertListener(ctx context.Context, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, newIsActive bool, newEndTime *time.Time, newDuration int) (uuid.UUID, error)
 	InsertUserListenerRequest(listenerID uuid.UUID, userID uuid.UUID, requestTime time.Time, endTime time.Time) error
 	GetStreamerByUsername(ctx context.Context, username string) (*struct {
 		ID         uuid.UUID
@@ -288,15 +646,20 @@ type ListenPostgresRepository interface {
 		EndTime    *time.Time
 		Duration   int
 	}, error)
-	GetActiveListeners() ([]domain.ActiveListenerData, error)
-	GetUserRequestsForListener(listenerID uuid.UUID) ([]struct {
-		UserID      uuid.UUID
-		RequestTime time.Time
-		EndTime     time.Time
-	}, error)
+	GetDesiredListeners(ctx context.Context) ([]domain.DesiredListener, error)
+	DeactivateUnrequestedListeners(ctx context.Context) (int64, error)
 	InsertMessage(listenerID uuid.UUID, senderUsername, content string, timestamp time.Time, hasLink bool, extractedLinks []string) error
+	InsertMessages(ctx context.Context, messages []domain.ChatMessage) error
+	InsertChatEvent(listenerID uuid.UUID, eventType, kickEventID string, payload []byte, occurredAt time.Time) error
+	InsertConnectionAttempts(ctx context.Context, attempts []domain.ConnectionAttempt) error
 	UpdateListenerStatus(listenerID uuid.UUID, isActive bool) error
 	UpdateListenerEndTime(ctx context.Context, listenerID uuid.UUID, endTime time.Time) error
+	UpdateListenerState(ctx context.Context, listenerIDs []uuid.UUID, state string, changedAt time.Time, lastError string) error
+	SetListenerSinks(ctx context.Context, listenerID uuid.UUID, sinks []string) error
+	OpenListenRequest(ctx context.Context, streamerUsername string, kickUserID *int, profilePic *string, userID uuid.UUID, requestTime, endTime time.Time, quota time.Duration) (uuid.UUID, error)
+	SaveListenRequest(ctx context.Context, listenerID, userID uuid.UUID, requestTime, endTime time.Time, quota time.Duration) (uuid.UUID, error)
+	GetUserActiveRequest(ctx context.Context, userID uuid.UUID, streamer string) (*domain.ListenRequest, error)
+	EndListenRequest(ctx context.Context, listenerID, userID uuid.UUID) error
 	GetMessagesByListener(listenerID uuid.UUID, limit, offset int) ([]struct {
 		ID               uuid.UUID
 		SenderUsername   string
@@ -309,29 +672,106 @@ type ListenPostgresRepository interface {
 
 // UseCase interface and implementation
 type ListenUseCase interface {
-	Execute(fbrCtx *fiber.Ctx, ctx context.Context, username string) (string, error)
-	StartActiveListenersOnStartup() error
+	Execute(fbrCtx *fiber.Ctx, ctx context.Context, username string, duration time.Duration, sinks []string) (string, error)
+	Extend(fbrCtx *fiber.Ctx, ctx context.Context, username string, by time.Duration) (*ListenRequestStatus, error)
+	Shorten(fbrCtx *fiber.Ctx, ctx context.Context, username string, by time.Duration) (*ListenRequestStatus, error)
+	Unsubscribe(fbrCtx *fiber.Ctx, ctx context.Context, username string) (*UnsubscribeResult, error)
+	StartReconciler(ctx context.Context) error
+	Shutdown(ctx context.Context)
 	StopListener(username string) error
 	GetListenerStats() map[string]interface{}
 }
 
 type listenUseCase struct {
-	repo   ListenPostgresRepository
-	config *Config
+	repo     ListenPostgresRepository
+	config   *Config
+	pool     *PusherPool
+	writer   *MessageWriter
+	attempts *AttemptLog
+	fanout   *LiveFanout
+	resolver *ChannelResolver
+	// exporter is nil unless a message broker is configured
+	exporter *BusExporter
+	webhooks *WebhookDispatcher
+	sinks    *SinkPipeline
+
+	// coord is nil when running as a single instance
+	coord        ListenerCoordinator
+	cluster      ownership
+	reconcileNow chan struct{}
+	cancel       context.CancelFunc
+
+	// The sinks feed the writer, so Shutdown stops them in that order
+	stopSinks    context.CancelFunc
+	sinksDone    chan struct{}
+	stopWriter   context.CancelFunc
+	writerDone   chan struct{}
+	stopAttempts context.CancelFunc
+	attemptsDone chan struct{}
 }
 
-func NewListenUseCase(repo ListenPostgresRepository) ListenUseCase {
-	return &listenUseCase{
-		repo:   repo,
-		config: AppConfig,
+func NewListenUseCase(repo ListenPostgresRepository, fanout *LiveFanout, resolver *ChannelResolver, coord ListenerCoordinator, exporter *BusExporter, alerts *AlertEngine, webhooks *WebhookDispatcher, sinkSpecs []SinkSpec) (ListenUseCase, error) {
+	u := &listenUseCase{
+		repo:         repo,
+		config:       AppConfig,
+		writer:       NewMessageWriter(repo, AppConfig),
+		attempts:     NewAttemptLog(repo, AppConfig),
+		fanout:       fanout,
+		resolver:     resolver,
+		coord:        coord,
+		exporter:     exporter,
+		webhooks:     webhooks,
+		reconcileNow: make(chan struct{}, 1),
+	}
+
+	sinks, err := NewSinkPipeline(DefaultSinkRegistry(), sinkSpecs, SinkDeps{
+		Writer:   u.writer,
+		Repo:     repo,
+		Exporter: exporter,
+		Alerts:   alerts,
+		Webhooks: webhooks,
+	}, u.config)
+	if err != nil {
+		return nil, err
 	}
+	u.sinks = sinks
+
+	u.startPipeline()
+	u.pool = NewPusherPool(u.config, u.routeEvent, func(info *ListenerInfo, attempt int, delay time.Duration, err error) {
+		u.recordAttempt(info, "socket", attempt, delay, err)
+	})
+	return u, nil
 }
 
-// Enhanced Execute method with better error handling
-func (u *listenUseCase) Execute(fbrCtx *fiber.Ctx, ctx context.Context, username string) (string, error) {
+// startPipeline runs the sinks, the message writer and the attempt log until Shutdown drains them
+func (u *listenUseCase) startPipeline() {
+	writerCtx, stopWriter := context.WithCancel(context.Background())
+	sinksCtx, stopSinks := context.WithCancel(context.Background())
+	attemptsCtx, stopAttempts := context.WithCancel(context.Background())
+	u.stopWriter, u.writerDone = stopWriter, make(chan struct{})
+	u.stopSinks, u.sinksDone = stopSinks, make(chan struct{})
+	u.stopAttempts, u.attemptsDone = stopAttempts, make(chan struct{})
+
+	go func() {
+		defer close(u.writerDone)
+		u.writer.Run(writerCtx)
+	}()
+	go func() {
+		defer close(u.sinksDone)
+		u.sinks.Run(sinksCtx)
+	}()
+	go func() {
+		defer close(u.attemptsDone)
+		u.attempts.Run(attemptsCtx)
+	}()
+}
+
+// Execute starts (or joins) listening to username for duration; zero means the default
+// sinks picks the outputs for this request, empty means every configured sink.
+func (u *listenUseCase) Execute(fbrCtx *fiber.Ctx, ctx context.Context, username string, duration time.Duration, sinks []string) (string, error) {
 	// Validate input
 	if username == "" {
-		return "", fmt.Errorf("username cannot be empty")
+		return "", fmt.Errorf("%w: username cannot be empty", domain.ErrInvalidInput)
 	}
 
 	userData, ok := middleware.GetUserData(fbrCtx)
@@ -344,414 +784,363 @@ func (u *listenUseCase) Execute(fbrCtx *fiber.Ctx, ctx context.Context, username
 		return "", domain.ErrNotFoundAuthorization
 	}
 
-	durationToAdd := 5 * time.Hour
-	endTimeToAdd := time.Now().Add(durationToAdd)
+	if duration == 0 {
+		duration = u.config.DefaultListenDuration
+	}
+	if err := u.validateDuration(duration); err != nil {
+		return "", err
+	}
+	if len(sinks) == 0 {
+		sinks = nil
+	}
+	if err := u.sinks.Validate(sinks); err != nil {
+		return "", err
+	}
 
-	// Get Kick user info with timeout context
-	kickCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
+	now := time.Now()
+	endTime := now.Add(duration)
 
-	kickUserInfo, err := u.getChatIdFromKickWithContext(kickCtx, username)
+	// A new POST never cuts short what the user already asked for, that is what shorten is for
+	if existing, err := u.repo.GetUserActiveRequest(ctx, currentUserID, username); err == nil {
+		if existing.EndTime.After(endTime) {
+			endTime = existing.EndTime
+		}
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+
+	kickUserInfo, err := u.resolver.Resolve(ctx, username)
 	if err != nil {
 		return "Sunucu hatası", fmt.Errorf("kullanıcı bilgileri alınamadı: %w", err)
 	}
 
+	// Every user gets their own listeners row; the request row holds their window.
+	// The quota is checked in the same transaction, a rejected request writes nothing.
+	listenerID, err := u.repo.OpenListenRequest(ctx, username, &kickUserInfo.KickUserID, &kickUserInfo.ProfilePic,
+		currentUserID, now, endTime, u.config.UserListenQuota)
+	if err != nil {
+		return "Veritabanı hatası", err
+	}
+	if err := u.repo.SetListenerSinks(ctx, listenerID, sinks); err != nil {
+		return "Veritabanı hatası", err
+	}
+
 	// Handle listener logic
 	listenerInfo, exists := ListenerManager.GetListener(username)
 
+	// Another replica owns this streamer, it picks the request up from the database
+	if (!exists || listenerInfo.Stopped()) && !u.claim(ctx, username) {
+		u.notifyCluster(ctx)
+		return fmt.Sprintf("'%s' kullanıcısının sohbet dinleme isteği kaydedildi", username), nil
+	}
+
 	if !exists {
 		// Create new listener
-		return u.createNewListener(ctx, username, currentUserID, endTimeToAdd, durationToAdd, kickUserInfo)
+		return u.createNewListener(username, currentUserID, listenerID, endTime, sinks)
 	}
 
-	// Update existing listener
-	return u.updateExistingListener(ctx, listenerInfo, currentUserID, endTimeToAdd, username)
-}
-
-func (u *listenUseCase) createNewListener(ctx context.Context, username string, userID uuid.UUID, endTime time.Time, duration time.Duration, kickInfo *KickUserInfo) (string, error) {
-	kickUserID := &kickInfo.User.ID
-	profilePic := &kickInfo.User.ProfilePic
-
-	listenerID, err := u.repo.InsertListener(
-		ctx,
-		username,
-		kickUserID,
-		profilePic,
-		userID,
-		true,
-		&endTime,
-		int(duration.Seconds()),
-	)
-	if err != nil {
-		return "Veritabanı hatası", fmt.Errorf("listener oluşturulamadı: %w", err)
+	// A listener whose last request was just removed is shutting down, start a fresh one
+	if listenerInfo.Stopped() {
+		return u.createNewListener(username, currentUserID, listenerID, endTime, sinks)
 	}
 
+	// Update existing listener
+	return u.updateExistingListener(listenerInfo, currentUserID, listenerID, endTime, sinks, username)
+}
+
+func (u *listenUseCase) createNewListener(username string, userID, listenerID uuid.UUID, endTime time.Time, sinks []string) (string, error) {
 	listenerInfo := &ListenerInfo{
 		Username:       username,
 		UserRequests:   make(map[uuid.UUID]UserRequestInfo),
 		OverallEndTime: endTime,
 		ListenerDBID:   listenerID,
-		DataChannel:    make(chan Data, u.config.MessageBufferSize),
+		EventChannel:   make(chan ChatEvent, u.config.MessageBufferSize),
+		ErrorChannel:   make(chan error, 1),
 		StopChannel:    make(chan struct{}),
 		LastActivity:   time.Now(),
 	}
 
-	listenerInfo.AddUserRequest(userID, endTime)
+	listenerInfo.AddUserRequest(userID, listenerID, endTime, sinks)
 	ListenerManager.AddListener(username, listenerInfo)
 
-	go u.startListening(listenerInfo)
+	u.launch(listenerInfo)
 
 	return fmt.Sprintf("'%s' kullanıcısının sohbeti dinlenmeye başlandı", username), nil
 }
 
-func (u *listenUseCase) updateExistingListener(ctx context.Context, listenerInfo *ListenerInfo, userID uuid.UUID, endTime time.Time, username string) (string, error) {
-	listenerInfo.AddUserRequest(userID, endTime)
+func (u *listenUseCase) updateExistingListener(listenerInfo *ListenerInfo, userID, listenerID uuid.UUID, endTime time.Time, sinks []string, username string) (string, error) {
+	listenerInfo.AddUserRequest(userID, listenerID, endTime, sinks)
 
-	// Update DB if necessary
-	if endTime.After(listenerInfo.OverallEndTime) {
-		if err := u.repo.UpdateListenerEndTime(ctx, listenerInfo.ListenerDBID, endTime); err != nil {
-			log.Printf("Listener end time güncellenirken hata: %v", err)
-		}
-	}
-
-	// Restart if not active
-	if !listenerInfo.IsActive() {
-		go u.startListening(listenerInfo)
+	// Restart unless a goroutine already owns it, even one that has not started yet
+	if u.launch(listenerInfo) {
 		return fmt.Sprintf("'%s' kullanıcısının sohbeti yeniden başlatıldı", username), nil
 	}
 
 	return fmt.Sprintf("'%s' kullanıcısının sohbet dinleme süresi güncellendi", username), nil
 }
 
-// Enhanced startListening with better error handling and reconnection
+// launch starts startListening unless a goroutine already owns info. The claim is
+// made before the goroutine runs, so two callers can never start it twice; never
+// start startListening directly.
+func (u *listenUseCase) launch(info *ListenerInfo) bool {
+	if !info.claim() {
+		return false
+	}
+	go u.startListening(info)
+	return true
+}
+
+// startListening supervises the listener until it is stopped or its end time passes.
+// Failures are retried with exponential backoff; the listener is only given up on early
+// when Pusher refuses reconnects.
 func (u *listenUseCase) startListening(info *ListenerInfo) {
+	// Ownership is given up last, after cleanup has detached the listener
+	defer info.owned.Store(false)
 	defer u.cleanupListener(info)
 
-	info.SetActive(true)
+	info.OnStateChange(u.persistState)
+	if info.CurrentState().Terminal() {
+		info.Transition(StatePending, nil)
+	}
 	log.Printf("'%s' için sohbet dinleme başlatılıyor", info.Username)
 
+	backoff := NewBackoff(u.config)
+	info.mu.Lock()
+	info.backoff = backoff
+	info.mu.Unlock()
 	for {
 		select {
 		case <-info.StopChannel:
 			log.Printf("'%s' için stop signal alındı", info.Username)
+			info.Transition(StateStopped, nil)
 			return
 		default:
-			if err := u.runListeningLoop(info); err != nil {
-				log.Printf("'%s' için listening loop hatası: %v", info.Username, err)
+		}
 
-				if info.ReconnectAttempts >= u.config.MaxReconnectAttempts {
-					log.Printf("'%s' için maksimum reconnect denemesi aşıldı", info.Username)
-					return
-				}
+		if !u.hasTimeLeft(info) {
+			log.Printf("'%s' için dinleme süresi doldu", info.Username)
+			info.Transition(StateExpired, nil)
+			return
+		}
 
-				info.ReconnectAttempts++
-				time.Sleep(u.config.ReconnectInterval)
-				continue
+		err := u.runListeningLoop(info)
+		backoff.MarkDisconnected()
+		if err == nil {
+			if info.Stopped() {
+				info.Transition(StateStopped, nil)
+			} else {
+				info.Transition(StateExpired, nil)
 			}
 			return
 		}
-	}
-}
 
-func (u *listenUseCase) runListeningLoop(info *ListenerInfo) error {
-	chatId, err := u.getChatId(info.Username)
-	if err != nil {
-		return fmt.Errorf("chat ID alınamadı: %w", err)
-	}
-
-	conn, err := u.connectWebSocket(chatId)
-	if err != nil {
-		return fmt.Errorf("websocket bağlantısı kurulamadı: %w", err)
-	}
-	defer conn.Close()
+		log.Printf("'%s' için listening loop hatası: %v", info.Username, err)
+		if reconnectActionFor(err) == DoNotReconnect {
+			log.Printf("'%s' için Pusher yeniden bağlanmaya izin vermiyor", info.Username)
+			info.Transition(StateFailed, err)
+			return
+		}
+		info.Transition(StateReconnecting, err)
 
-	info.Client = conn
-	info.ReconnectAttempts = 0 // Reset on successful connection
+		delay, attempt := backoff.Next()
+		if remaining := time.Until(info.EndTime()); delay > remaining {
+			delay = max(remaining, 0)
+		}
+		u.recordAttempt(info, "listener", attempt, delay, err)
 
-	// Start message reading goroutine
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+		select {
+		case <-info.StopChannel:
+			log.Printf("'%s' için stop signal alındı", info.Username)
+			info.Transition(StateStopped, nil)
+			return
+		case <-time.After(delay):
+		}
+	}
+}
 
-	go u.readMessages(ctx, info, conn)
+// recordAttempt counts and persists a reconnect so flapping channels are visible
+func (u *listenUseCase) recordAttempt(info *ListenerInfo, scope string, attempt int, delay time.Duration, cause error) {
+	total := info.RecordReconnect()
+	log.Printf("'%s' için %s yeniden bağlanma #%d (toplam %d), %s sonra: %v", info.Username, scope, attempt, total, delay.Round(time.Millisecond), cause)
 
-	// Main processing loop
-	return u.processMessages(info, cancel)
+	reason := ""
+	if cause != nil {
+		reason = cause.Error()
+	}
+	u.attempts.Record(domain.ConnectionAttempt{
+		ListenerID:  info.ListenerDBID,
+		Scope:       scope,
+		Attempt:     attempt,
+		Delay:       delay,
+		Reason:      reason,
+		AttemptedAt: time.Now(),
+	})
 }
 
-func (u *listenUseCase) connectWebSocket(chatId int) (*websocket.Conn, error) {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
+// persistState mirrors a transition onto every listeners row the listener stands for
+func (u *listenUseCase) persistState(change ListenerStateChange) {
+	log.Printf("'%s' durumu: %s -> %s", change.Username, change.From, change.To)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := u.repo.UpdateListenerState(ctx, change.ListenerIDs, string(change.To), change.At, change.LastError); err != nil {
+			log.Printf("'%s' için dinleyici durumu kaydedilemedi: %v", change.Username, err)
+		}
+	}()
+}
 
-	conn, _, err := dialer.Dial(u.config.WebSocketUrl, nil)
+func (u *listenUseCase) runListeningLoop(info *ListenerInfo) error {
+	info.Transition(StateResolving, nil)
+	chatId, err := u.getChatId(info.Username)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("chat ID alınamadı: %w", err)
 	}
 
-	subscribeMsg := fmt.Sprintf(u.config.ChatroomSubscribeCommand, chatId)
-	if err := conn.WriteMessage(websocket.TextMessage, []byte(subscribeMsg)); err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("subscribe mesajı gönderilemedi: %w", err)
+	info.Transition(StateConnecting, nil)
+	if err := u.pool.Subscribe(chatId, info); err != nil {
+		return fmt.Errorf("chatroom aboneliği başarısız: %w", err)
 	}
+	defer u.pool.Unsubscribe(chatId, info)
 
-	return conn, nil
-}
-
-func (u *listenUseCase) readMessages(ctx context.Context, info *ListenerInfo, conn *websocket.Conn) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			_, msgByte, err := conn.ReadMessage()
-			if err != nil {
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-					log.Printf("'%s' için WebSocket normal şekilde kapatıldı", info.Username)
-				} else {
-					log.Printf("'%s' için mesaj okuma hatası: %v", info.Username, err)
-				}
-				return
-			}
+	info.mu.Lock()
+	info.ChatroomID = chatId
+	info.mu.Unlock()
 
-			go u.unmarshallAndSendToChannel(info, msgByte)
-		}
-	}
+	// Main processing loop
+	return u.processMessages(info)
 }
 
-func (u *listenUseCase) processMessages(info *ListenerInfo, cancel context.CancelFunc) error {
+func (u *listenUseCase) processMessages(info *ListenerInfo) error {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case data := <-info.DataChannel:
-			u.handleMessage(info, data)
+		case event := <-info.EventChannel:
+			u.handleEvent(info, event)
+
+		case err := <-info.ErrorChannel:
+			return err
 
 		case <-ticker.C:
-			if !u.shouldContinueListening(info) {
-				cancel()
+			if !u.hasTimeLeft(info) {
 				return nil
 			}
+			// A quiet chat on a healthy socket is paused, not dead
+			if info.CurrentState() == StateSubscribed && info.IdleSince() > u.config.ListenerIdleAfter {
+				info.Transition(StatePaused, nil)
+			}
 
 		case <-info.StopChannel:
-			cancel()
 			return nil
 		}
 	}
 }
 
-func (u *listenUseCase) shouldContinueListening(info *ListenerInfo) bool {
-	if !info.IsActive() {
-		return false
-	}
-
+func (u *listenUseCase) hasTimeLeft(info *ListenerInfo) bool {
 	info.RemoveExpiredRequests()
 	return info.HasActiveRequests() || time.Now().Before(info.OverallEndTime)
 }
 
-func (u *listenUseCase) handleMessage(info *ListenerInfo, data Data) {
-	// Display message with color
-	if linkRegex.MatchString(data.Content) {
-		links := linkRegex.FindAllString(data.Content, -1)
-		fmt.Print(aurora.Colorize(
-			fmt.Sprintf("💬 %s:%s:%s\n", info.Username, data.Sender.Username, data.Content),
-			utils.GetColorFromHex(data.Sender.Identity.Color),
-		))
-		for _, link := range links {
-			fmt.Print(aurora.Colorize(
-				fmt.Sprintf("🔗 [%s] %s LINK: %s\n", info.Username, data.Sender.Username, link),
-				aurora.YellowFg|aurora.BoldFm,
-			))
-		}
-	} else {
-		fmt.Print(aurora.Colorize(
-			fmt.Sprintf("💬 %s:%s:%s\n", info.Username, data.Sender.Username, data.Content),
-			utils.GetColorFromHex(data.Sender.Identity.Color),
-		))
-	}
-
-	// Save to database asynchronously
-	// go u.saveMessageToDB(info, data)
+func (u *listenUseCase) handleEvent(info *ListenerInfo, event ChatEvent) {
+	info.Touch()
+	u.fanout.Publish(info.Username, event)
+
+	// Each sink has its own buffer, a slow one drops its own events instead of stalling us
+	info.mu.RLock()
+	listenerID := info.ListenerDBID
+	info.mu.RUnlock()
+	u.sinks.Dispatch(SinkEvent{
+		Streamer:   info.Username,
+		ListenerID: listenerID,
+		Event:      event,
+		ReceivedAt: time.Now(),
+	}, info.SinkSet())
 }
 
 func (u *listenUseCase) cleanupListener(info *ListenerInfo) {
 	log.Printf("'%s' için cleanup başlatılıyor", info.Username)
 
-	info.SetActive(false)
-
-	if err := u.repo.UpdateListenerStatus(info.ListenerDBID, false); err != nil {
-		log.Printf("'%s' için veritabanı durumu güncellenirken hata: %v", info.Username, err)
+	// Every exit path sets a terminal state; this only catches panics and early returns
+	if !info.CurrentState().Terminal() {
+		info.Transition(StateStopped, nil)
 	}
 
-	ListenerManager.RemoveListener(info.Username)
+	ListenerManager.Detach(info)
 
-	if info.Client != nil {
-		info.Client.Close()
+	// A replacement listener may already hold the lease under the same key
+	if current, ok := ListenerManager.GetListener(info.Username); !ok || current.Stopped() {
+		u.releaseLease(info.Username)
 	}
 
 	log.Printf("'%s' için cleanup tamamlandı", info.Username)
 }
 
-// API helper methods with better error handling
+// getChatId resolves through the cache, so a reconnect does not hit Kick again
 func (u *listenUseCase) getChatId(username string) (int, error) {
-	if knownId := getKnownChatId(username); knownId != 0 {
-		return knownId, nil
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), u.config.ResolverTimeout*3)
+	defer cancel()
 
-	info, err := GetChatIdFromKick(username)
+	info, err := u.resolver.Resolve(ctx, username)
 	if err != nil {
 		return 0, err
 	}
-
-	return info.Chatroom.ID, nil
-}
-
-func (u *listenUseCase) getChatIdFromKickWithContext(ctx context.Context, username string) (*KickUserInfo, error) {
-	type result struct {
-		info *KickUserInfo
-		err  error
-	}
-
-	ch := make(chan result, 1)
-	go func() {
-		info, err := GetChatIdFromKick(username)
-		ch <- result{info, err}
-	}()
-
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case res := <-ch:
-		return res.info, res.err
-	}
+	return info.ChatroomID, nil
 }
 
 // Additional utility methods
+// StopListener tears the streamer down for every user, see Unsubscribe for a single user
 func (u *listenUseCase) StopListener(username string) error {
 	listenerInfo, exists := ListenerManager.GetListener(username)
 	if !exists {
 		return fmt.Errorf("listener not found for username: %s", username)
 	}
 
-	select {
-	case listenerInfo.StopChannel <- struct{}{}:
-		return nil
-	default:
-		return fmt.Errorf("failed to send stop signal")
-	}
+	listenerInfo.Stop()
+	return nil
 }
 
 func (u *listenUseCase) GetListenerStats() map[string]interface{} {
-	return map[string]interface{}{
-		"active_listeners": ListenerManager.GetActiveListenerCount(),
-		"total_listeners":  len(ListenerManager.listeners),
+	stats := map[string]interface{}{
+		"active_listeners":     ListenerManager.GetActiveListenerCount(),
+		"total_listeners":      len(ListenerManager.listeners),
+		"pusher_connections":   u.pool.ConnectionCount(),
+		"pusher_subscriptions": u.pool.SubscriptionCount(),
+		"message_writer":       u.writer.Stats(),
+		"live_fanout":          u.fanout.Stats(),
+		"channel_providers":    u.resolver.ProviderStates(),
+		"listener_states":      ListenerManager.StateCounts(),
 	}
-}
-
-
-
-// Message processing
-func (u *listenUseCase) unmarshallAndSendToChannel(info *ListenerInfo, msgByte []byte) {
-	var event Message
-	if err := json.Unmarshal(msgByte, &event); err != nil {
-		log.Printf("JSON unmarshal event hatası: %v", err)
-		return
+	stats["sinks"] = u.sinks.Stats()
+	if u.exporter != nil {
+		stats["bus_exporter"] = u.exporter.Stats()
 	}
-
-	if event.Event == "App\\Events\\ChatMessageEvent" {
-		var rawDataString string
-		if err := json.Unmarshal(event.Data, &rawDataString); err != nil {
-			log.Printf("JSON unmarshal rawDataString hatası: %v", err)
-			return
-		}
-
-		var data Data
-		if err := json.Unmarshal([]byte(rawDataString), &data); err != nil {
-			log.Printf("JSON unmarshal data hatası: %v", err)
-			return
-		}
-
-		if data.Type == "message" {
-			select {
-			case info.DataChannel <- data:
-				// Successfully sent
-			default:
-				log.Printf("'%s' için data channel dolu, mesaj atlanıyor", info.Username)
-			}
-		}
-	}
-}
-
-func (u *listenUseCase) saveMessageToDB(info *ListenerInfo, data Data) {
-	hasLink := linkRegex.MatchString(data.Content)
-	var extractedLinks []string
-
-	if hasLink {
-		extractedLinks = linkRegex.FindAllString(data.Content, -1)
-	}
-
-	err := u.repo.InsertMessage(info.ListenerDBID, data.Sender.Username, data.Content, data.Timestamp, hasLink, extractedLinks)
-	if err != nil {
-		log.Printf("'%s' için mesaj veritabanına kaydedilirken hata: %v", info.Username, err)
-	}
-}
-
-// Helper functions (kept same for compatibility)
-func getKnownChatId(username string) int {
-	knownChatIds := map[string]int{
-		// Add known chat IDs here
-	}
-	if chatId, exists := knownChatIds[username]; exists {
-		return chatId
-	}
-	return 0
-}
-
-var GetChatIdFromKick = func(username string) (*KickUserInfo, error) {
-	methods := []func(string) (*KickUserInfo, error){
-		getChatIdFromVercelAPI,
+	if u.webhooks != nil {
+		stats["webhooks"] = u.webhooks.Stats()
 	}
-	for _, method := range methods {
-		chatId, err := method(username)
-		if err == nil {
-			return chatId, nil
-		}
-	}
-	return nil, fmt.Errorf("tüm yöntemler başarısız")
+	return stats
 }
 
-func getChatIdFromVercelAPI(username string) (*KickUserInfo, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	url := fmt.Sprintf("https://kick-api-provider.vercel.app/api/channel?username=%s", username)
-
-	req, err := http.NewRequest("GET", url, nil)
+// Message processing, called by the pusher pool for frames routed to info
+func (u *listenUseCase) routeEvent(info *ListenerInfo, event Message) {
+	chatEvent, ok, err := DecodeEvent(event)
 	if err != nil {
-		return nil, fmt.Errorf("istek oluşturulamadı: %w", err)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("istek gönderilemedi: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("istek başarısız: HTTP %d", resp.StatusCode)
+		log.Printf("'%s' için event çözülemedi: %v", info.Username, err)
+		return
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("cevap okunamadı: %w", err)
+	if !ok {
+		return
 	}
 
-	var result KickUserInfo
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("JSON parse hatası: %w", err)
+	// Kick also sends non-chat message types (e.g. celebrations) as ChatMessageEvent
+	if data, isMessage := chatEvent.(*Data); isMessage && data.Type != "message" && data.Type != "reply" {
+		return
 	}
 
-	if result.Chatroom.ID == 0 {
-		return nil, fmt.Errorf("chat ID bulunamadı (0 döndü)")
+	select {
+	case info.EventChannel <- chatEvent:
+		// Successfully sent
+	default:
+		log.Printf("'%s' için event channel dolu, %s atlanıyor", info.Username, chatEvent.EventType())
 	}
-
-	return &result, nil
 }
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListenRequestStatus describes the caller's request after a change
+type ListenRequestStatus struct {
+	Streamer        string    `json:"streamer"`
+	RequestID       uuid.UUID `json:"request_id"`
+	EndTime         time.Time `json:"end_time"`
+	RemainingSecs   int64     `json:"remaining_seconds"`
+	ListenerEndTime time.Time `json:"listener_end_time"`
+}
+
+func (u *listenUseCase) validateDuration(d time.Duration) error {
+	if d < u.config.MinListenDuration {
+		return fmt.Errorf("%w: süre en az %s olmalı", domain.ErrInvalidInput, u.config.MinListenDuration)
+	}
+	if d > u.config.MaxListenDuration {
+		return fmt.Errorf("%w: süre en fazla %s olabilir", domain.ErrInvalidInput, u.config.MaxListenDuration)
+	}
+	return nil
+}
+
+func (u *listenUseCase) Extend(fbrCtx *fiber.Ctx, ctx context.Context, username string, by time.Duration) (*ListenRequestStatus, error) {
+	return u.adjust(fbrCtx, ctx, username, by)
+}
+
+func (u *listenUseCase) Shorten(fbrCtx *fiber.Ctx, ctx context.Context, username string, by time.Duration) (*ListenRequestStatus, error) {
+	return u.adjust(fbrCtx, ctx, username, -by)
+}
+
+// adjust moves the end of the caller's open request by delta and re-derives the listener window
+func (u *listenUseCase) adjust(fbrCtx *fiber.Ctx, ctx context.Context, username string, delta time.Duration) (*ListenRequestStatus, error) {
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	currentUserID, err := uuid.Parse(userData.UserID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	if delta == 0 {
+		return nil, fmt.Errorf("%w: süre sıfır olamaz", domain.ErrInvalidInput)
+	}
+
+	request, err := u.repo.GetUserActiveRequest(ctx, currentUserID, username)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime := request.EndTime.Add(delta)
+	if err := u.validateDuration(time.Until(endTime)); err != nil {
+		return nil, err
+	}
+	// Shortening only frees quota
+	var quota time.Duration
+	if delta > 0 {
+		quota = u.config.UserListenQuota
+	}
+	requestID, err := u.repo.SaveListenRequest(ctx, request.ListenerID, currentUserID, request.RequestTime, endTime, quota)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ListenRequestStatus{
+		Streamer:        username,
+		RequestID:       requestID,
+		EndTime:         endTime,
+		RemainingSecs:   int64(time.Until(endTime).Seconds()),
+		ListenerEndTime: endTime,
+	}
+	if listenerInfo, exists := ListenerManager.GetListener(username); exists {
+		if !listenerInfo.SetUserRequestEnd(currentUserID, endTime) {
+			listenerInfo.AddUserRequest(currentUserID, request.ListenerID, endTime, request.Sinks)
+		}
+		status.ListenerEndTime = listenerInfo.EndTime()
+	} else {
+		u.notifyCluster(ctx)
+	}
+	return status, nil
+}
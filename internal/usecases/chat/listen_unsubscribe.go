@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"kick-chat/internal/middleware"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UnsubscribeResult struct {
+	Message           string    `json:"message"`
+	RemainingRequests int       `json:"remaining_requests"`
+	Stopped           bool      `json:"stopped"`
+	ListenerEndTime   time.Time `json:"listener_end_time,omitempty"`
+}
+
+// Unsubscribe ends only the caller's request. The socket keeps running for the
+// other users and is closed once nobody is left.
+func (u *listenUseCase) Unsubscribe(fbrCtx *fiber.Ctx, ctx context.Context, username string) (*UnsubscribeResult, error) {
+	if username == "" {
+		return nil, fmt.Errorf("%w: username cannot be empty", domain.ErrInvalidInput)
+	}
+
+	userData, ok := middleware.GetUserData(fbrCtx)
+	if !ok {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	currentUserID, err := uuid.Parse(userData.UserID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	request, err := u.repo.GetUserActiveRequest(ctx, currentUserID, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.EndListenRequest(ctx, request.ListenerID, currentUserID); err != nil {
+		return nil, err
+	}
+
+	result := &UnsubscribeResult{
+		Message: fmt.Sprintf("'%s' kullanıcısının sohbet dinleme isteğiniz sonlandırıldı", username),
+	}
+
+	listenerInfo, exists := ListenerManager.GetListener(username)
+	if !exists {
+		// The owning replica, if any, stops it on its next reconcile
+		u.notifyCluster(ctx)
+		result.Stopped = u.coord == nil
+		return result, nil
+	}
+
+	remaining, _ := listenerInfo.RemoveUserRequest(currentUserID)
+	result.RemainingRequests = remaining
+	if remaining == 0 {
+		log.Printf("'%s' için son dinleme isteği kaldırıldı, dinleyici durduruluyor", username)
+		listenerInfo.Stop()
+		result.Stopped = true
+		return result, nil
+	}
+
+	result.ListenerEndTime = listenerInfo.EndTime()
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListenerState is the lifecycle state of a streamer listener
+type ListenerState string
+
+const (
+	StatePending      ListenerState = "pending"      // created, goroutine not started yet
+	StateResolving    ListenerState = "resolving"    // looking up the chatroom ID
+	StateConnecting   ListenerState = "connecting"   // subscribe sent, waiting for Pusher to confirm
+	StateSubscribed   ListenerState = "subscribed"   // confirmed and receiving events
+	StateReconnecting ListenerState = "reconnecting" // socket or subscription lost, backing off
+	StatePaused       ListenerState = "paused"       // subscribed but the chat has been silent for a while
+	StateExpired      ListenerState = "expired"      // every request ran out
+	StateFailed       ListenerState = "failed"       // gave up, e.g. Pusher said not to reconnect
+	StateStopped      ListenerState = "stopped"      // stopped on purpose
+)
+
+var ErrInvalidTransition = errors.New("geçersiz dinleyici durum geçişi")
+
+var listenerTransitions = map[ListenerState][]ListenerState{
+	StatePending:      {StateResolving, StateExpired, StateFailed, StateStopped},
+	StateResolving:    {StateConnecting, StateReconnecting, StateExpired, StateFailed, StateStopped},
+	StateConnecting:   {StateSubscribed, StateReconnecting, StateExpired, StateFailed, StateStopped},
+	StateSubscribed:   {StatePaused, StateReconnecting, StateExpired, StateFailed, StateStopped},
+	StatePaused:       {StateSubscribed, StateReconnecting, StateExpired, StateFailed, StateStopped},
+	StateReconnecting: {StateResolving, StateConnecting, StateSubscribed, StateExpired, StateFailed, StateStopped},
+	StateExpired:      {StatePending},
+	StateFailed:       {StatePending},
+	StateStopped:      {StatePending},
+}
+
+func (s ListenerState) CanTransition(to ListenerState) bool {
+	for _, next := range listenerTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Terminal states end the listening goroutine; only a new request moves them back to pending
+func (s ListenerState) Terminal() bool {
+	return s == StateExpired || s == StateFailed || s == StateStopped
+}
+
+// Running reports whether a listening goroutine owns the listener
+func (s ListenerState) Running() bool {
+	return s != StatePending && !s.Terminal()
+}
+
+type ListenerStateChange struct {
+	Username    string
+	From        ListenerState
+	To          ListenerState
+	At          time.Time
+	LastError   string
+	ListenerIDs []uuid.UUID
+}
+
+// Transition moves the listener to state `to`, recording cause as the last error.
+// Moving to the current state is a no-op.
+func (l *ListenerInfo) Transition(to ListenerState, cause error) error {
+	l.mu.Lock()
+	from := l.State
+	if from == "" {
+		from = StatePending
+	}
+	if from == to {
+		l.mu.Unlock()
+		return nil
+	}
+	if !from.CanTransition(to) {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	now := time.Now()
+	l.State = to
+	l.StateChangedAt = now
+	if cause != nil {
+		l.LastError = cause.Error()
+	}
+	if to == StateSubscribed {
+		l.ReconnectAttempts = 0 // Reset once Pusher confirms the subscription
+	}
+	change := ListenerStateChange{
+		Username:    l.Username,
+		From:        from,
+		To:          to,
+		At:          now,
+		LastError:   l.LastError,
+		ListenerIDs: l.listenerIDsLocked(),
+	}
+	hook := l.stateHook
+	l.mu.Unlock()
+
+	if hook != nil {
+		hook(change)
+	}
+	return nil
+}
+
+// OnStateChange registers the callback that persists transitions
+func (l *ListenerInfo) OnStateChange(hook func(ListenerStateChange)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stateHook = hook
+}
+
+func (l *ListenerInfo) CurrentState() ListenerState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.State == "" {
+		return StatePending
+	}
+	return l.State
+}
+
+// listenerIDsLocked returns every listeners row this in-memory listener stands for
+func (l *ListenerInfo) listenerIDsLocked() []uuid.UUID {
+	ids := []uuid.UUID{l.ListenerDBID}
+	seen := map[uuid.UUID]struct{}{l.ListenerDBID: {}}
+	for _, request := range l.UserRequests {
+		if _, ok := seen[request.ListenerID]; ok || request.ListenerID == uuid.Nil {
+			continue
+		}
+		seen[request.ListenerID] = struct{}{}
+		ids = append(ids, request.ListenerID)
+	}
+	return ids
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// EventBus carries live events between instances, see infra/cluster
+type EventBus interface {
+	Publish(ctx context.Context, streamer string, payload []byte) error
+	Subscribe(ctx context.Context, handle func(streamer string, payload []byte)) error
+	Replay(ctx context.Context, streamer string, limit int) ([][]byte, error)
+}
+
+// wireEvent is the bus payload. Keys are kept short since every chat line crosses
+// Redis once per instance; the streamer travels in the channel name.
+type wireEvent struct {
+	Origin     string          `json:"o"`
+	Type       EventType       `json:"t"`
+	ReceivedAt int64           `json:"r"`
+	Data       json.RawMessage `json:"d"`
+}
+
+type outgoingEvent struct {
+	streamer string
+	payload  []byte
+}
+
+// eventTypeFactories maps our own event type names back to their payload types
+var eventTypeFactories = func() map[EventType]func() ChatEvent {
+	factories := make(map[EventType]func() ChatEvent, len(eventFactories))
+	for _, factory := range eventFactories {
+		factories[factory().EventType()] = factory
+	}
+	return factories
+}()
+
+func encodeWireEvent(origin string, event ChatEvent, receivedAt time.Time) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(wireEvent{
+		Origin:     origin,
+		Type:       event.EventType(),
+		ReceivedAt: receivedAt.UnixMilli(),
+		Data:       data,
+	})
+}
+
+func decodeWireEvent(payload []byte) (wireEvent, ChatEvent, error) {
+	var wire wireEvent
+	if err := json.Unmarshal(payload, &wire); err != nil {
+		return wire, nil, err
+	}
+	factory, ok := eventTypeFactories[wire.Type]
+	if !ok {
+		return wire, nil, fmt.Errorf("bilinmeyen olay tipi: %s", wire.Type)
+	}
+	event := factory()
+	if len(wire.Data) > 0 {
+		if err := json.Unmarshal(wire.Data, event); err != nil {
+			return wire, nil, err
+		}
+	}
+	return wire, event, nil
+}
+
+// LiveFanout feeds the local hub and, with a bus, every other instance's hub, so
+// a client sees a streamer whichever replica owns its listener.
+// A nil bus keeps everything in process.
+type LiveFanout struct {
+	hub        *LiveHub
+	bus        EventBus
+	instanceID string
+	outgoing   chan outgoingEvent
+
+	published atomic.Int64
+	received  atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+}
+
+type LiveFanoutStats struct {
+	Published int64 `json:"published"`
+	Received  int64 `json:"received"`
+	Dropped   int64 `json:"dropped"`
+	Failed    int64 `json:"failed"`
+}
+
+func NewLiveFanout(hub *LiveHub, bus EventBus, instanceID string, config *Config) *LiveFanout {
+	return &LiveFanout{
+		hub:        hub,
+		bus:        bus,
+		instanceID: instanceID,
+		outgoing:   make(chan outgoingEvent, config.FanoutQueueSize),
+	}
+}
+
+// Publish delivers to local clients right away and queues the event for the bus.
+// It never blocks the listener: a full queue drops the remote copy only.
+func (f *LiveFanout) Publish(streamer string, event ChatEvent) {
+	receivedAt := time.Now()
+	f.hub.PublishAt(streamer, event, receivedAt)
+	if f.bus == nil {
+		return
+	}
+
+	payload, err := encodeWireEvent(f.instanceID, event, receivedAt)
+	if err != nil {
+		log.Printf("'%s' olayı kodlanamadı: %v", streamer, err)
+		return
+	}
+	select {
+	case f.outgoing <- outgoingEvent{streamer: streamer, payload: payload}:
+	default:
+		f.dropped.Add(1)
+	}
+}
+
+// Run publishes queued events and relays the other instances' events into the
+// local hub until ctx is done
+func (f *LiveFanout) Run(ctx context.Context) {
+	if f.bus == nil {
+		return
+	}
+	go f.receive(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case out := <-f.outgoing:
+			pubCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			err := f.bus.Publish(pubCtx, out.streamer, out.payload)
+			cancel()
+			if err != nil {
+				f.failed.Add(1)
+				continue
+			}
+			f.published.Add(1)
+		}
+	}
+}
+
+func (f *LiveFanout) receive(ctx context.Context) {
+	backoff := NewBackoff(AppConfig)
+	for {
+		err := f.bus.Subscribe(ctx, f.relay)
+		if ctx.Err() != nil {
+			return
+		}
+		delay, _ := backoff.Next()
+		log.Printf("Canlı olay aboneliği koptu, %s sonra tekrar denenecek: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (f *LiveFanout) relay(streamer string, payload []byte) {
+	wire, event, err := decodeWireEvent(payload)
+	if err != nil {
+		log.Printf("'%s' için gelen olay çözülemedi: %v", streamer, err)
+		return
+	}
+	// Our own events already went to the local hub in Publish
+	if wire.Origin == f.instanceID {
+		return
+	}
+	f.received.Add(1)
+	f.hub.PublishAt(streamer, event, time.UnixMilli(wire.ReceivedAt))
+}
+
+// Warm seeds the hub backlog of streamers this instance has not seen events for
+// yet from the bus replay window, so a client on any replica starts with context
+func (f *LiveFanout) Warm(ctx context.Context, streamers ...string) {
+	if f.bus == nil {
+		return
+	}
+	for _, streamer := range streamers {
+		if f.hub.HasBacklog(streamer) {
+			continue
+		}
+		payloads, err := f.bus.Replay(ctx, streamer, f.hub.backlogSize)
+		if err != nil {
+			log.Printf("'%s' için geçmiş olaylar alınamadı: %v", streamer, err)
+			continue
+		}
+		events := make([]LiveEvent, 0, len(payloads))
+		for _, payload := range payloads {
+			wire, event, err := decodeWireEvent(payload)
+			if err != nil {
+				continue
+			}
+			events = append(events, f.hub.newEvent(streamer, event, time.UnixMilli(wire.ReceivedAt)))
+		}
+		f.hub.Seed(streamer, events)
+	}
+}
+
+func (f *LiveFanout) Stats() LiveFanoutStats {
+	return LiveFanoutStats{
+		Published: f.published.Load(),
+		Received:  f.received.Load(),
+		Dropped:   f.dropped.Load(),
+		Failed:    f.failed.Load(),
+	}
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LiveFeedRepository interface {
+	GetMessagesAfterKickID(ctx context.Context, streamers []string, kickMessageID string, limit int) ([]domain.StoredMessage, error)
+	GetUserStreamers(ctx context.Context, userID uuid.UUID) ([]string, error)
+	CanReadStreamer(ctx context.Context, userID uuid.UUID, streamer string) (bool, error)
+}
+
+type LiveFeedUseCase interface {
+	Join(ctx context.Context, userID string, streamers ...string) (*LiveSubscription, []LiveEvent, error)
+	Leave(sub *LiveSubscription)
+	OwnedStreamers(ctx context.Context, userID string) ([]string, error)
+	Resume(ctx context.Context, streamers []string, lastEventID string) ([]LiveEvent, error)
+}
+
+type liveFeedUseCase struct {
+	hub    *LiveHub
+	fanout *LiveFanout
+	repo   LiveFeedRepository
+	config *Config
+}
+
+func NewLiveFeedUseCase(hub *LiveHub, fanout *LiveFanout, repo LiveFeedRepository) LiveFeedUseCase {
+	return &liveFeedUseCase{
+		hub:    hub,
+		fanout: fanout,
+		repo:   repo,
+		config: AppConfig,
+	}
+}
+
+// Join subscribes to streamers the user listens to, or has listened to, like the history endpoints
+func (u *liveFeedUseCase) Join(ctx context.Context, userID string, streamers ...string) (*LiveSubscription, []LiveEvent, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil, domain.ErrNotFoundAuthorization
+	}
+	if len(streamers) == 0 {
+		return nil, nil, fmt.Errorf("%w: username cannot be empty", domain.ErrInvalidInput)
+	}
+	for _, streamer := range streamers {
+		if streamer == "" {
+			return nil, nil, fmt.Errorf("%w: username cannot be empty", domain.ErrInvalidInput)
+		}
+	}
+	if err := u.authorize(ctx, id, streamers); err != nil {
+		return nil, nil, err
+	}
+
+	// The listener may run on another replica, start from its replay window
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	u.fanout.Warm(ctx, streamers...)
+	cancel()
+
+	sub, backlog := u.hub.Subscribe(streamers...)
+	return sub, backlog, nil
+}
+
+// authorize trusts requests this replica holds in memory and asks the database for the rest
+func (u *liveFeedUseCase) authorize(ctx context.Context, userID uuid.UUID, streamers []string) error {
+	listening := make(map[string]struct{})
+	for _, s := range ListenerManager.StreamersForUser(userID) {
+		listening[s] = struct{}{}
+	}
+	for _, streamer := range streamers {
+		if _, ok := listening[streamer]; ok {
+			continue
+		}
+		allowed, err := u.repo.CanReadStreamer(ctx, userID, streamer)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return domain.ErrForbidden
+		}
+	}
+	return nil
+}
+
+func (u *liveFeedUseCase) Leave(sub *LiveSubscription) {
+	u.hub.Unsubscribe(sub)
+}
+
+// OwnedStreamers merges the user's persisted listeners with in-memory requests
+func (u *liveFeedUseCase) OwnedStreamers(ctx context.Context, userID string) ([]string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	streamers, err := u.repo.GetUserStreamers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(streamers))
+	for _, s := range streamers {
+		seen[s] = struct{}{}
+	}
+	for _, s := range ListenerManager.StreamersForUser(id) {
+		if _, ok := seen[s]; !ok {
+			streamers = append(streamers, s)
+			seen[s] = struct{}{}
+		}
+	}
+	sort.Strings(streamers)
+	return streamers, nil
+}
+
+// Resume replays stored messages that came after lastEventID (a Kick message id)
+func (u *liveFeedUseCase) Resume(ctx context.Context, streamers []string, lastEventID string) ([]LiveEvent, error) {
+	if lastEventID == "" {
+		return nil, nil
+	}
+
+	messages, err := u.repo.GetMessagesAfterKickID(ctx, streamers, lastEventID, u.config.LiveResumeLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]LiveEvent, 0, len(messages))
+	for _, m := range messages {
+		events = append(events, LiveEvent{
+			ID:         m.KickMessageID,
+			Streamer:   m.Streamer,
+			Type:       EventTypeMessage,
+			ReceivedAt: m.Timestamp,
+			Data:       storedMessageToData(m),
+		})
+	}
+	return events, nil
+}
+
+func storedMessageToData(m domain.StoredMessage) *Data {
+	return &Data{
+		Type:    "message",
+		ID:      m.KickMessageID,
+		Content: m.Content,
+		Sender: Sender{
+			ID:       m.SenderID,
+			Username: m.SenderUsername,
+			Identity: Identity{Color: m.SenderColor},
+		},
+		Timestamp: m.Timestamp,
+	}
+}
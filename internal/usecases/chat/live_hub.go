@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LiveEvent is the JSON shape pushed to live API clients
+type LiveEvent struct {
+	Seq        uint64    `json:"seq"`
+	ID         string    `json:"id,omitempty"`
+	Streamer   string    `json:"streamer"`
+	Type       EventType `json:"type"`
+	ReceivedAt time.Time `json:"received_at"`
+	Data       ChatEvent `json:"data"`
+}
+
+// LiveSubscription is one connected client; C is closed when the hub drops it
+type LiveSubscription struct {
+	C         <-chan LiveEvent
+	Streamers []string
+
+	ch         chan LiveEvent
+	slow       atomic.Bool
+	closedOnce sync.Once
+}
+
+// Slow reports whether the hub disconnected this client for not keeping up
+func (s *LiveSubscription) Slow() bool {
+	return s.slow.Load()
+}
+
+func (s *LiveSubscription) close() {
+	s.closedOnce.Do(func() { close(s.ch) })
+}
+
+type liveRoom struct {
+	backlog     []LiveEvent
+	next        int
+	full        bool
+	subs        map[*LiveSubscription]struct{}
+	lastEventAt time.Time
+}
+
+// liveRoomSweepInterval is how often Run looks for idle rooms
+const liveRoomSweepInterval = 1 * time.Minute
+
+// LiveHub fans listener events out to any number of API clients per streamer,
+// keeping the last BacklogSize events so new clients start with context.
+// Every replica relays every streamer, so rooms nobody watches are evicted once
+// they have been quiet for LiveReplayTTL; Warm seeds them again on the next join.
+type LiveHub struct {
+	backlogSize  int
+	clientBuffer int
+	idleAfter    time.Duration
+	seq          atomic.Uint64
+
+	mu    sync.RWMutex
+	rooms map[string]*liveRoom
+}
+
+func NewLiveHub(config *Config) *LiveHub {
+	return &LiveHub{
+		backlogSize:  config.LiveBacklogSize,
+		clientBuffer: config.LiveClientBufferSize,
+		idleAfter:    config.LiveReplayTTL,
+		rooms:        make(map[string]*liveRoom),
+	}
+}
+
+// Run evicts idle rooms until ctx is cancelled
+func (h *LiveHub) Run(ctx context.Context) {
+	ticker := time.NewTicker(liveRoomSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.evictIdle()
+		}
+	}
+}
+
+// evictIdle drops rooms without subscribers whose last event is older than idleAfter
+func (h *LiveHub) evictIdle() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for streamer, room := range h.rooms {
+		if len(room.subs) == 0 && time.Since(room.lastEventAt) >= h.idleAfter {
+			delete(h.rooms, streamer)
+		}
+	}
+}
+
+func (h *LiveHub) roomLocked(streamer string) *liveRoom {
+	room, ok := h.rooms[streamer]
+	if !ok {
+		room = &liveRoom{
+			backlog: make([]LiveEvent, h.backlogSize),
+			subs:    make(map[*LiveSubscription]struct{}),
+		}
+		h.rooms[streamer] = room
+	}
+	return room
+}
+
+func (h *LiveHub) newEvent(streamer string, event ChatEvent, receivedAt time.Time) LiveEvent {
+	return LiveEvent{
+		Seq:        h.seq.Add(1),
+		ID:         eventID(event),
+		Streamer:   streamer,
+		Type:       event.EventType(),
+		ReceivedAt: receivedAt,
+		Data:       event,
+	}
+}
+
+// Publish never blocks: a client whose buffer is full is disconnected instead
+func (h *LiveHub) Publish(streamer string, event ChatEvent) {
+	h.PublishAt(streamer, event, time.Now())
+}
+
+// PublishAt is Publish for events received elsewhere, keeping their original time
+func (h *LiveHub) PublishAt(streamer string, event ChatEvent, receivedAt time.Time) {
+	live := h.newEvent(streamer, event, receivedAt)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.roomLocked(streamer)
+	room.append(live, h.backlogSize)
+
+	for sub := range room.subs {
+		select {
+		case sub.ch <- live:
+		default:
+			sub.slow.Store(true)
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// Subscribe registers a client on one or more streamers and returns their
+// combined backlog, oldest first
+func (h *LiveHub) Subscribe(streamers ...string) (*LiveSubscription, []LiveEvent) {
+	ch := make(chan LiveEvent, h.clientBuffer)
+	sub := &LiveSubscription{C: ch, Streamers: streamers, ch: ch}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []LiveEvent
+	for _, streamer := range streamers {
+		room := h.roomLocked(streamer)
+		room.subs[sub] = struct{}{}
+		backlog = append(backlog, room.snapshot()...)
+	}
+	if len(streamers) > 1 {
+		sort.Slice(backlog, func(i, j int) bool { return backlog[i].Seq < backlog[j].Seq })
+	}
+	return sub, backlog
+}
+
+func (h *LiveHub) Unsubscribe(sub *LiveSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(sub)
+}
+
+func (h *LiveHub) removeLocked(sub *LiveSubscription) {
+	for _, streamer := range sub.Streamers {
+		if room, ok := h.rooms[streamer]; ok {
+			delete(room.subs, sub)
+			if len(room.subs) == 0 && !room.full && room.next == 0 {
+				delete(h.rooms, streamer)
+			}
+		}
+	}
+	sub.close()
+}
+
+// HasBacklog reports whether any event was kept for streamer
+func (h *LiveHub) HasBacklog(streamer string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	room, ok := h.rooms[streamer]
+	return ok && (room.full || room.next > 0)
+}
+
+// Seed fills an empty backlog, events published meanwhile win
+func (h *LiveHub) Seed(streamer string, events []LiveEvent) {
+	if h.backlogSize == 0 || len(events) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.roomLocked(streamer)
+	if room.full || room.next > 0 {
+		return
+	}
+	if len(events) > h.backlogSize {
+		events = events[len(events)-h.backlogSize:]
+	}
+	for _, live := range events {
+		room.append(live, h.backlogSize)
+	}
+}
+
+func (h *LiveHub) ClientCount(streamer string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if room, ok := h.rooms[streamer]; ok {
+		return len(room.subs)
+	}
+	return 0
+}
+
+func (r *liveRoom) append(live LiveEvent, size int) {
+	// Arrival time, a seeded backlog starts its idle clock when it is seeded
+	r.lastEventAt = time.Now()
+	if size == 0 {
+		return
+	}
+	r.backlog[r.next] = live
+	r.next = (r.next + 1) % size
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *liveRoom) snapshot() []LiveEvent {
+	if !r.full {
+		return append([]LiveEvent(nil), r.backlog[:r.next]...)
+	}
+	events := make([]LiveEvent, 0, len(r.backlog))
+	events = append(events, r.backlog[r.next:]...)
+	return append(events, r.backlog[:r.next]...)
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"kick-chat/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MessageExportRepository interface {
+	CanReadListener(ctx context.Context, userID, listenerID uuid.UUID) (bool, error)
+	CanReadStreamer(ctx context.Context, userID uuid.UUID, streamer string) (bool, error)
+	StreamMessages(ctx context.Context, filter domain.ExportFilter, fn func(domain.StoredMessage) error) error
+}
+
+// ExportQuery picks the messages and the output format of an export.
+// Exactly one of ListenerID and Streamer must be set.
+type ExportQuery struct {
+	ListenerID string
+	Streamer   string
+	Sender     string
+	From       *time.Time
+	To         *time.Time
+	Format     string
+	Subtitles  SubtitleOptions
+}
+
+// messageEncoder writes messages in one export format; close writes any trailer
+type messageEncoder interface {
+	encode(msg domain.StoredMessage) error
+	close() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	newEncoder  func(w io.Writer, meta exportMeta) messageEncoder
+	subtitles   bool
+}
+
+// exportMeta is what encoders know about the export besides the rows
+type exportMeta struct {
+	Streamer    string
+	From        *time.Time
+	To          *time.Time
+	GeneratedAt time.Time
+	Subtitles   SubtitleOptions
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":   {contentType: "text/csv; charset=utf-8", extension: "csv", newEncoder: newCSVEncoder},
+	"jsonl": {contentType: "application/x-ndjson", extension: "jsonl", newEncoder: newJSONLEncoder},
+	"html":  {contentType: "text/html; charset=utf-8", extension: "html", newEncoder: newHTMLEncoder},
+	"vtt":   {contentType: "text/vtt; charset=utf-8", extension: "vtt", newEncoder: newVTTEncoder, subtitles: true},
+	"srt":   {contentType: "application/x-subrip; charset=utf-8", extension: "srt", newEncoder: newSRTEncoder, subtitles: true},
+}
+
+// ChatExport is a validated export; nothing is read until WriteTo
+type ChatExport struct {
+	ContentType string
+	FileName    string
+
+	repo   MessageExportRepository
+	filter domain.ExportFilter
+	format exportFormat
+	meta   exportMeta
+}
+
+// WriteTo streams the messages oldest first and returns how many were written
+func (e *ChatExport) WriteTo(ctx context.Context, w io.Writer) (int, error) {
+	buffered := bufio.NewWriterSize(w, 64<<10)
+	encoder := e.format.newEncoder(buffered, e.meta)
+
+	count := 0
+	err := e.repo.StreamMessages(ctx, e.filter, func(msg domain.StoredMessage) error {
+		count++
+		return encoder.encode(msg)
+	})
+	if err != nil {
+		return count, err
+	}
+	if err := encoder.close(); err != nil {
+		return count, err
+	}
+	return count, buffered.Flush()
+}
+
+type MessageExportUseCase interface {
+	// Prepare validates a query without access checks, for operators using the CLI
+	Prepare(query ExportQuery) (*ChatExport, error)
+	Open(ctx context.Context, userID string, query ExportQuery) (*ChatExport, error)
+}
+
+type messageExportUseCase struct {
+	repo   MessageExportRepository
+	config *Config
+}
+
+func NewMessageExportUseCase(repo MessageExportRepository) MessageExportUseCase {
+	return &messageExportUseCase{
+		repo:   repo,
+		config: AppConfig,
+	}
+}
+
+func (u *messageExportUseCase) Prepare(query ExportQuery) (*ChatExport, error) {
+	formatName := strings.ToLower(strings.TrimSpace(query.Format))
+	if formatName == "" {
+		formatName = "csv"
+	}
+	format, ok := exportFormats[formatName]
+	if !ok {
+		return nil, fmt.Errorf("%w: bilinmeyen format: %s", domain.ErrInvalidInput, formatName)
+	}
+
+	filter := domain.ExportFilter{
+		Streamer: strings.TrimSpace(query.Streamer),
+		Sender:   strings.TrimSpace(query.Sender),
+		From:     query.From,
+		To:       query.To,
+	}
+	name := filter.Streamer
+	switch {
+	case query.ListenerID != "" && filter.Streamer != "":
+		return nil, fmt.Errorf("%w: listener_id ve streamer birlikte kullanılamaz", domain.ErrInvalidInput)
+	case query.ListenerID != "":
+		listenerID, err := uuid.Parse(query.ListenerID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: geçersiz dinleyici id", domain.ErrInvalidInput)
+		}
+		filter.ListenerID = &listenerID
+		name = listenerID.String()
+	case filter.Streamer == "":
+		return nil, fmt.Errorf("%w: listener_id ya da streamer gerekli", domain.ErrInvalidInput)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from, to değerinden önce olmalı", domain.ErrInvalidInput)
+	}
+
+	var subtitles SubtitleOptions
+	if format.subtitles {
+		var err error
+		if subtitles, err = u.subtitleOptions(query.Subtitles, filter.From); err != nil {
+			return nil, err
+		}
+		// Messages before the broadcast are never shown, no need to read them
+		if filter.From == nil || filter.From.Before(*subtitles.Start) {
+			filter.From = subtitles.Start
+		}
+	}
+
+	if filter.From != nil {
+		name += "-" + filter.From.UTC().Format("20060102")
+	}
+	return &ChatExport{
+		ContentType: format.contentType,
+		FileName:    name + "-chat." + format.extension,
+		repo:        u.repo,
+		filter:      filter,
+		format:      format,
+		meta: exportMeta{
+			Streamer:    filter.Streamer,
+			From:        filter.From,
+			To:          filter.To,
+			GeneratedAt: time.Now(),
+			Subtitles:   subtitles,
+		},
+	}, nil
+}
+
+// subtitleOptions fills defaults; the broadcast start falls back to from
+func (u *messageExportUseCase) subtitleOptions(opts SubtitleOptions, from *time.Time) (SubtitleOptions, error) {
+	if opts.Start == nil {
+		opts.Start = from
+	}
+	if opts.Start == nil {
+		return opts, fmt.Errorf("%w: altyazı için yayın başlangıcı (start) gerekli", domain.ErrInvalidInput)
+	}
+
+	if opts.CueDuration == 0 {
+		opts.CueDuration = u.config.SubtitleCueDuration
+	}
+	if opts.CueDuration < time.Second || opts.CueDuration > u.config.SubtitleMaxCueDuration {
+		return opts, fmt.Errorf("%w: gösterim süresi 1s ile %s arasında olmalı", domain.ErrInvalidInput, u.config.SubtitleMaxCueDuration)
+	}
+
+	if opts.MaxLines == 0 {
+		opts.MaxLines = u.config.SubtitleMaxLines
+	}
+	if opts.MaxLines < 1 || opts.MaxLines > u.config.SubtitleMaxLinesLimit {
+		return opts, fmt.Errorf("%w: satır sayısı 1 ile %d arasında olmalı", domain.ErrInvalidInput, u.config.SubtitleMaxLinesLimit)
+	}
+
+	opts.SenderFormat = strings.ToLower(strings.TrimSpace(opts.SenderFormat))
+	switch opts.SenderFormat {
+	case "":
+		opts.SenderFormat = SenderFormatColor
+	case SenderFormatColor, SenderFormatPlain, SenderFormatNone:
+	default:
+		return opts, fmt.Errorf("%w: sender_format color, plain ya da none olmalı", domain.ErrInvalidInput)
+	}
+	return opts, nil
+}
+
+func (u *messageExportUseCase) Open(ctx context.Context, userID string, query ExportQuery) (*ChatExport, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	export, err := u.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed bool
+	if export.filter.ListenerID != nil {
+		allowed, err = u.repo.CanReadListener(ctx, currentUserID, *export.filter.ListenerID)
+	} else {
+		allowed, err = u.repo.CanReadStreamer(ctx, currentUserID, export.filter.Streamer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, domain.ErrForbidden
+	}
+	export.filter.UserID = &currentUserID
+	return export, nil
+}
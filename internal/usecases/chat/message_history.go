@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MessageHistoryRepository interface {
+	CanReadListener(ctx context.Context, userID, listenerID uuid.UUID) (bool, error)
+	GetMessages(ctx context.Context, filter domain.MessageFilter) ([]domain.StoredMessage, error)
+}
+
+// MessageQuery is the user facing form of domain.MessageFilter, cursor still encoded
+type MessageQuery struct {
+	ListenerID string
+	Sender     string
+	From       *time.Time
+	To         *time.Time
+	HasLink    *bool
+	Contains   string
+	Cursor     string
+	Order      string
+	Limit      int
+}
+
+type MessagePage struct {
+	Messages   []domain.StoredMessage `json:"messages"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+	HasMore    bool                   `json:"has_more"`
+}
+
+type MessageHistoryUseCase interface {
+	List(ctx context.Context, userID string, query MessageQuery) (*MessagePage, error)
+}
+
+type messageHistoryUseCase struct {
+	repo   MessageHistoryRepository
+	config *Config
+}
+
+func NewMessageHistoryUseCase(repo MessageHistoryRepository) MessageHistoryUseCase {
+	return &messageHistoryUseCase{
+		repo:   repo,
+		config: AppConfig,
+	}
+}
+
+func (u *messageHistoryUseCase) List(ctx context.Context, userID string, query MessageQuery) (*MessagePage, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	filter, err := u.buildFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = currentUserID
+
+	allowed, err := u.repo.CanReadListener(ctx, currentUserID, filter.ListenerID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, domain.ErrForbidden
+	}
+
+	// One extra row tells us whether another page exists without a COUNT query
+	limit := filter.Limit
+	filter.Limit++
+	messages, err := u.repo.GetMessages(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		page.HasMore = true
+		last := page.Messages[limit-1]
+		page.NextCursor = encodeMessageCursor(domain.MessageCursor{Timestamp: last.Timestamp, ID: last.ID})
+	}
+	if page.Messages == nil {
+		page.Messages = []domain.StoredMessage{}
+	}
+	return page, nil
+}
+
+func (u *messageHistoryUseCase) buildFilter(query MessageQuery) (domain.MessageFilter, error) {
+	listenerID, err := uuid.Parse(query.ListenerID)
+	if err != nil {
+		return domain.MessageFilter{}, fmt.Errorf("%w: geçersiz dinleyici id", domain.ErrInvalidInput)
+	}
+
+	filter := domain.MessageFilter{
+		ListenerID: listenerID,
+		Sender:     strings.TrimSpace(query.Sender),
+		From:       query.From,
+		To:         query.To,
+		HasLink:    query.HasLink,
+		Contains:   strings.TrimSpace(query.Contains),
+		Limit:      query.Limit,
+	}
+
+	switch strings.ToLower(query.Order) {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, fmt.Errorf("%w: order 'asc' ya da 'desc' olmalı", domain.ErrInvalidInput)
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, fmt.Errorf("%w: from, to değerinden önce olmalı", domain.ErrInvalidInput)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = u.config.HistoryDefaultLimit
+	}
+	if filter.Limit > u.config.HistoryMaxLimit {
+		filter.Limit = u.config.HistoryMaxLimit
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeMessageCursor(query.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = &cursor
+	}
+	return filter, nil
+}
+
+// Cursors are opaque to clients: base64url("<RFC3339Nano timestamp>|<message id>")
+func encodeMessageCursor(c domain.MessageCursor) string {
+	raw := c.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeMessageCursor(s string) (domain.MessageCursor, error) {
+	invalid := fmt.Errorf("%w: geçersiz cursor", domain.ErrInvalidInput)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.MessageCursor{}, invalid
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return domain.MessageCursor{}, invalid
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return domain.MessageCursor{}, invalid
+	}
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.MessageCursor{}, invalid
+	}
+	return domain.MessageCursor{Timestamp: timestamp, ID: messageID}, nil
+}
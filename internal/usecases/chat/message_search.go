@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MessageSearchRepository interface {
+	SearchMessages(ctx context.Context, filter domain.SearchFilter) (*domain.SearchResult, error)
+}
+
+type SearchQuery struct {
+	Query     string
+	Streamers string // comma separated
+	Sender    string
+	From      *time.Time
+	To        *time.Time
+	Page      int
+	Limit     int
+}
+
+type SearchPage struct {
+	*domain.SearchResult
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+	Pages int `json:"pages"`
+}
+
+type MessageSearchUseCase interface {
+	Search(ctx context.Context, userID string, query SearchQuery) (*SearchPage, error)
+}
+
+type messageSearchUseCase struct {
+	repo   MessageSearchRepository
+	config *Config
+}
+
+func NewMessageSearchUseCase(repo MessageSearchRepository) MessageSearchUseCase {
+	return &messageSearchUseCase{
+		repo:   repo,
+		config: AppConfig,
+	}
+}
+
+func (u *messageSearchUseCase) Search(ctx context.Context, userID string, query SearchQuery) (*SearchPage, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	text := strings.TrimSpace(query.Query)
+	if text == "" {
+		return nil, fmt.Errorf("%w: arama metni boş olamaz", domain.ErrInvalidInput)
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, fmt.Errorf("%w: from, to değerinden önce olmalı", domain.ErrInvalidInput)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = u.config.SearchDefaultLimit
+	}
+	if limit > u.config.SearchMaxLimit {
+		limit = u.config.SearchMaxLimit
+	}
+	page := query.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	var streamers []string
+	for _, s := range strings.Split(query.Streamers, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			streamers = append(streamers, s)
+		}
+	}
+
+	result, err := u.repo.SearchMessages(ctx, domain.SearchFilter{
+		UserID:    currentUserID,
+		Query:     text,
+		Streamers: streamers,
+		Sender:    strings.TrimSpace(query.Sender),
+		From:      query.From,
+		To:        query.To,
+		Limit:     limit,
+		Offset:    (page - 1) * limit,
+		FacetSize: u.config.SearchFacetSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchPage{
+		SearchResult: result,
+		Page:         page,
+		Limit:        limit,
+		Pages:        (result.Total + limit - 1) / limit,
+	}, nil
+}
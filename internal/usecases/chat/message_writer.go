@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"kick-chat/domain"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+type MessageWriterRepository interface {
+	InsertMessages(ctx context.Context, messages []domain.ChatMessage) error
+}
+
+// MessageWriter is a bounded write-behind buffer in front of the messages table.
+// Enqueue never blocks: when the buffer is full the message is dropped and counted.
+type MessageWriter struct {
+	repo          MessageWriterRepository
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	queue         chan domain.ChatMessage
+
+	enqueued atomic.Int64
+	written  atomic.Int64
+	dropped  atomic.Int64
+	failed   atomic.Int64
+}
+
+type MessageWriterStats struct {
+	Enqueued int64 `json:"enqueued"`
+	Written  int64 `json:"written"`
+	Dropped  int64 `json:"dropped"`
+	Failed   int64 `json:"failed"`
+	Pending  int   `json:"pending"`
+}
+
+func NewMessageWriter(repo MessageWriterRepository, config *Config) *MessageWriter {
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &MessageWriter{
+		repo:          repo,
+		batchSize:     batchSize,
+		flushInterval: config.FlushInterval,
+		maxRetries:    config.WriteMaxRetries,
+		queue:         make(chan domain.ChatMessage, config.WriteQueueSize),
+	}
+}
+
+func (w *MessageWriter) Enqueue(msg domain.ChatMessage) bool {
+	select {
+	case w.queue <- msg:
+		w.enqueued.Add(1)
+		return true
+	default:
+		w.dropped.Add(1)
+		return false
+	}
+}
+
+func (w *MessageWriter) Stats() MessageWriterStats {
+	return MessageWriterStats{
+		Enqueued: w.enqueued.Load(),
+		Written:  w.written.Load(),
+		Dropped:  w.dropped.Load(),
+		Failed:   w.failed.Load(),
+		Pending:  len(w.queue),
+	}
+}
+
+// Run flushes whenever BatchSize messages are buffered or FlushInterval passes.
+// On ctx cancellation the remaining buffer is drained before returning.
+func (w *MessageWriter) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	reportTicker := time.NewTicker(1 * time.Minute)
+	defer reportTicker.Stop()
+
+	batch := make([]domain.ChatMessage, 0, w.batchSize)
+	var lastReport MessageWriterStats
+
+	for {
+		select {
+		case msg := <-w.queue:
+			batch = append(batch, msg)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-reportTicker.C:
+			lastReport = w.report(lastReport)
+
+		case <-ctx.Done():
+			w.drain(batch)
+			w.report(lastReport)
+			return
+		}
+	}
+}
+
+func (w *MessageWriter) drain(batch []domain.ChatMessage) {
+	for {
+		select {
+		case msg := <-w.queue:
+			batch = append(batch, msg)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				w.flush(batch)
+			}
+			return
+		}
+	}
+}
+
+func (w *MessageWriter) flush(batch []domain.ChatMessage) {
+	var err error
+	delay := 200 * time.Millisecond
+	for attempt := 0; attempt <= w.maxRetries; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = w.repo.InsertMessages(ctx, batch)
+		cancel()
+		if err == nil {
+			w.written.Add(int64(len(batch)))
+			return
+		}
+		if !errors.Is(err, domain.ErrTransient) {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	w.failed.Add(int64(len(batch)))
+	log.Printf("%d mesaj veritabanına yazılamadı: %v", len(batch), err)
+}
+
+// report logs drop/failure counters when they changed since the last report
+func (w *MessageWriter) report(last MessageWriterStats) MessageWriterStats {
+	current := w.Stats()
+	if current.Dropped != last.Dropped || current.Failed != last.Failed {
+		log.Printf("Mesaj yazıcı: yazılan=%d düşürülen=%d başarısız=%d bekleyen=%d",
+			current.Written, current.Dropped, current.Failed, current.Pending)
+	}
+	return current
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"kick-chat/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MyListenersRepository interface {
+	GetUserListeners(ctx context.Context, userID uuid.UUID) ([]domain.UserListener, error)
+}
+
+// MyListener is a stored request enriched with what this process knows about its socket
+type MyListener struct {
+	domain.UserListener
+	RemainingSeconds  int64      `json:"remaining_seconds"`
+	Live              bool       `json:"live"`
+	ChatroomID        int        `json:"chatroom_id,omitempty"`
+	ReconnectAttempts int        `json:"reconnect_attempts"`
+	LastActivity      *time.Time `json:"last_activity,omitempty"`
+	LastEventAt       *time.Time `json:"last_event_at,omitempty"`
+	ListenerEndTime   *time.Time `json:"listener_end_time,omitempty"`
+	SharedWith        int        `json:"shared_with"`
+}
+
+type MyListenersUseCase interface {
+	List(ctx context.Context, userID string) ([]MyListener, error)
+}
+
+type myListenersUseCase struct {
+	repo MyListenersRepository
+}
+
+func NewMyListenersUseCase(repo MyListenersRepository) MyListenersUseCase {
+	return &myListenersUseCase{
+		repo: repo,
+	}
+}
+
+func (u *myListenersUseCase) List(ctx context.Context, userID string) ([]MyListener, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	stored, err := u.repo.GetUserListeners(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	listeners := make([]MyListener, 0, len(stored))
+	for _, s := range stored {
+		item := MyListener{
+			UserListener:     s,
+			RemainingSeconds: int64(s.EndTime.Sub(now).Seconds()),
+		}
+
+		if info, exists := ListenerManager.GetListener(s.Streamer); exists {
+			// In-memory state is newer than the row, which is written asynchronously
+			status := info.Status()
+			item.Live = true
+			if status.State != "" {
+				item.State = string(status.State)
+				item.StateChangedAt = &status.StateChangedAt
+				item.LastError = status.LastError
+			}
+			// Last activity is the last chat event, or the subscribe before the first one
+			if !status.LastEventAt.IsZero() {
+				item.LastEventAt = &status.LastEventAt
+				item.LastActivity = &status.LastEventAt
+			} else if !status.LastActivity.IsZero() {
+				item.LastActivity = &status.LastActivity
+			}
+			item.ChatroomID = status.ChatroomID
+			item.ReconnectAttempts = status.ReconnectAttempts
+			item.ListenerEndTime = &status.OverallEndTime
+			// Other users sharing the same socket
+			item.SharedWith = max(status.RequestCount-1, 0)
+		}
+		listeners = append(listeners, item)
+	}
+	return listeners, nil
+}
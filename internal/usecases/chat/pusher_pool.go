@@ -0,0 +1,511 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// PusherPool multiplexes many chatrooms.%d.v2 subscriptions over a small
+// number of shared websocket connections and routes frames by channel name.
+type PusherPool struct {
+	config      *Config
+	handler     func(info *ListenerInfo, msg Message)
+	onReconnect func(info *ListenerInfo, attempt int, delay time.Duration, err error)
+
+	mu     sync.Mutex
+	conns  []*pusherConn
+	routes map[string]*pusherConn
+	nextID int
+}
+
+type pusherConn struct {
+	id   int
+	pool *PusherPool
+
+	mu      sync.RWMutex
+	ws      *websocket.Conn
+	subs    map[string]*ListenerInfo
+	closed  bool
+	done    chan struct{}
+	backoff *Backoff
+	// pending holds (un)subscribe frames queued under the pool lock and written after it
+	pending []string
+
+	writeMu sync.Mutex
+}
+
+func NewPusherPool(config *Config, handler func(info *ListenerInfo, msg Message), onReconnect func(info *ListenerInfo, attempt int, delay time.Duration, err error)) *PusherPool {
+	return &PusherPool{
+		config:      config,
+		handler:     handler,
+		onReconnect: onReconnect,
+		routes:      make(map[string]*pusherConn),
+	}
+}
+
+func chatroomChannel(chatroomID int) string {
+	return fmt.Sprintf("chatrooms.%d.v2", chatroomID)
+}
+
+// Subscribe routes the chatroom's frames to info, reusing a connection with spare capacity.
+// The subscribe frame is queued under the pool lock, so it keeps its place relative to
+// other route changes, and written after the lock is released.
+func (p *PusherPool) Subscribe(chatroomID int, info *ListenerInfo) error {
+	channel := chatroomChannel(chatroomID)
+
+	p.mu.Lock()
+	conn, exists := p.routes[channel]
+	if !exists {
+		conn = p.pickConnLocked()
+		p.routes[channel] = conn
+	}
+
+	conn.mu.Lock()
+	conn.subs[channel] = info
+	if !exists {
+		conn.pending = append(conn.pending, fmt.Sprintf(p.config.ChatroomSubscribeCommand, channel))
+	}
+	conn.mu.Unlock()
+	p.mu.Unlock()
+
+	if exists {
+		// Already subscribed on the socket, only the route target changed
+		return nil
+	}
+	return conn.flush()
+}
+
+// Unsubscribe drops the route if it still points at info and closes idle connections
+func (p *PusherPool) Unsubscribe(chatroomID int, info *ListenerInfo) {
+	channel := chatroomChannel(chatroomID)
+
+	p.mu.Lock()
+	conn, exists := p.routes[channel]
+	if !exists {
+		p.mu.Unlock()
+		return
+	}
+
+	conn.mu.Lock()
+	if current := conn.subs[channel]; current != info {
+		conn.mu.Unlock()
+		p.mu.Unlock()
+		return
+	}
+	delete(conn.subs, channel)
+	idle := len(conn.subs) == 0
+	if !idle {
+		conn.pending = append(conn.pending, fmt.Sprintf(p.config.ChatroomUnsubscribeCommand, channel))
+	}
+	conn.mu.Unlock()
+
+	delete(p.routes, channel)
+	if idle {
+		p.removeConnLocked(conn)
+	}
+	p.mu.Unlock()
+
+	if idle {
+		conn.close()
+		return
+	}
+	if err := conn.flush(); err != nil {
+		log.Printf("pusher#%d: %s aboneliği kaldırılamadı: %v", conn.id, channel, err)
+	}
+}
+
+func (p *PusherPool) ConnectionCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns)
+}
+
+func (p *PusherPool) SubscriptionCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.routes)
+}
+
+func (p *PusherPool) pickConnLocked() *pusherConn {
+	for _, conn := range p.conns {
+		conn.mu.RLock()
+		free := len(conn.subs) < p.config.ChannelsPerConnection
+		conn.mu.RUnlock()
+		if free {
+			return conn
+		}
+	}
+
+	p.nextID++
+	conn := &pusherConn{
+		id:      p.nextID,
+		pool:    p,
+		subs:    make(map[string]*ListenerInfo),
+		done:    make(chan struct{}),
+		backoff: NewBackoff(p.config),
+	}
+	p.conns = append(p.conns, conn)
+	go conn.run()
+	return conn
+}
+
+// dropConn forgets a connection that gave up, so its channels get a fresh socket on resubscribe
+func (p *PusherPool) dropConn(conn *pusherConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removeConnLocked(conn)
+	for channel, routed := range p.routes {
+		if routed == conn {
+			delete(p.routes, channel)
+		}
+	}
+	conn.close()
+}
+
+func (p *PusherPool) removeConnLocked(conn *pusherConn) {
+	for i, c := range p.conns {
+		if c == conn {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			return
+		}
+	}
+}
+
+// run keeps the shared socket alive until the connection is closed by the pool
+func (c *pusherConn) run() {
+	for {
+		if c.isClosed() {
+			return
+		}
+
+		err := c.connectAndRead()
+		c.backoff.MarkDisconnected()
+		c.markAllUnsubscribed()
+		if err == nil || c.isClosed() {
+			if c.isClosed() {
+				return
+			}
+			err = errors.New("bağlantı sunucu tarafından kapatıldı")
+		}
+		log.Printf("pusher#%d bağlantı hatası: %v", c.id, err)
+
+		delay, attempt := c.backoff.Next()
+		switch reconnectActionFor(err) {
+		case DoNotReconnect:
+			// 4000-4099: the app or request is invalid, retrying the socket won't help
+			c.failAll(err)
+			c.pool.dropConn(c)
+			return
+		case ReconnectImmediately:
+			delay = 0
+		}
+		c.reportReconnect(attempt, delay, err)
+
+		select {
+		case <-c.done:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *pusherConn) connectAndRead() error {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	ws, _, err := dialer.Dial(c.pool.config.WebSocketUrl, nil)
+	if err != nil {
+		return fmt.Errorf("websocket bağlantısı kurulamadı: %w", err)
+	}
+	defer ws.Close()
+
+	activityTimeout, err := c.awaitEstablished(ws)
+	if err != nil {
+		return err
+	}
+	c.backoff.MarkConnected()
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.ws = ws
+	channels := make([]string, 0, len(c.subs))
+	for channel := range c.subs {
+		channels = append(channels, channel)
+	}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.ws = nil
+		c.mu.Unlock()
+	}()
+
+	// Resubscribe everything routed to this socket, including channels added while it was down
+	for _, channel := range channels {
+		if err := c.write(ws, fmt.Sprintf(c.pool.config.ChatroomSubscribeCommand, channel)); err != nil {
+			return fmt.Errorf("subscribe mesajı gönderilemedi: %w", err)
+		}
+	}
+	log.Printf("pusher#%d bağlandı, %d kanal (activity_timeout=%s)", c.id, len(channels), activityTimeout)
+
+	var lastSeen atomic.Int64
+	lastSeen.Store(time.Now().UnixNano())
+	deadErr := make(chan error, 1)
+	stopKeepalive := make(chan struct{})
+	defer close(stopKeepalive)
+	go c.keepalive(ws, activityTimeout, &lastSeen, deadErr, stopKeepalive)
+
+	for {
+		_, msgByte, err := ws.ReadMessage()
+		if err != nil {
+			select {
+			case dead := <-deadErr:
+				return dead
+			default:
+			}
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) && c.isClosed() {
+				return nil
+			}
+			return fmt.Errorf("mesaj okuma hatası: %w", err)
+		}
+		lastSeen.Store(time.Now().UnixNano())
+
+		var msg Message
+		if err := json.Unmarshal(msgByte, &msg); err != nil {
+			log.Printf("pusher#%d JSON unmarshal event hatası: %v", c.id, err)
+			continue
+		}
+
+		if err := c.handleFrame(ws, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// awaitEstablished reads the first frame, which must be pusher:connection_established
+func (c *pusherConn) awaitEstablished(ws *websocket.Conn) (time.Duration, error) {
+	ws.SetReadDeadline(time.Now().Add(c.pool.config.PongTimeout))
+	defer ws.SetReadDeadline(time.Time{})
+
+	var msg Message
+	if err := ws.ReadJSON(&msg); err != nil {
+		return 0, fmt.Errorf("connection_established beklenirken hata: %w", err)
+	}
+
+	switch msg.Event {
+	case pusherConnectionEstablished:
+		var established connectionEstablished
+		if err := decodePusherData(msg.Data, &established); err != nil {
+			return 0, fmt.Errorf("connection_established çözülemedi: %w", err)
+		}
+		if established.ActivityTimeout > 0 {
+			return time.Duration(established.ActivityTimeout) * time.Second, nil
+		}
+		return defaultActivityTimeout, nil
+	case pusherError:
+		var pusherErr PusherError
+		decodePusherData(msg.Data, &pusherErr)
+		return 0, &pusherErr
+	}
+	return 0, fmt.Errorf("beklenmeyen ilk event: %s", msg.Event)
+}
+
+// keepalive pings after activityTimeout of silence and kills the socket if no frame follows
+func (c *pusherConn) keepalive(ws *websocket.Conn, activityTimeout time.Duration, lastSeen *atomic.Int64, deadErr chan<- error, stop <-chan struct{}) {
+	for {
+		idleFor := time.Since(time.Unix(0, lastSeen.Load()))
+		wait := activityTimeout - idleFor
+		if wait > 0 {
+			select {
+			case <-stop:
+				return
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		pingSentAt := time.Now()
+		if err := c.write(ws, pusherPingFrame); err != nil {
+			deadErr <- fmt.Errorf("ping gönderilemedi: %w", err)
+			ws.Close()
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(c.pool.config.PongTimeout):
+		}
+
+		if time.Unix(0, lastSeen.Load()).Before(pingSentAt) {
+			deadErr <- ErrPusherDeadSocket
+			ws.Close()
+			return
+		}
+	}
+}
+
+func (c *pusherConn) handleFrame(ws *websocket.Conn, msg Message) error {
+	switch msg.Event {
+	case pusherPing:
+		if err := c.write(ws, pusherPongFrame); err != nil {
+			return fmt.Errorf("pong gönderilemedi: %w", err)
+		}
+		return nil
+
+	case pusherPong:
+		return nil
+
+	case pusherError:
+		var pusherErr PusherError
+		if err := decodePusherData(msg.Data, &pusherErr); err != nil {
+			log.Printf("pusher#%d pusher:error çözülemedi: %v", c.id, err)
+			return nil
+		}
+		// Errors without a code are informational, coded ones end the connection
+		if pusherErr.Code == 0 {
+			log.Printf("pusher#%d uyarı: %s", c.id, pusherErr.Message)
+			return nil
+		}
+		return &pusherErr
+
+	case pusherSubscriptionSucceeded:
+		if info, ok := c.route(msg.Channel); ok {
+			info.SetSubscribed(true)
+			log.Printf("'%s' için %s aboneliği onaylandı", info.Username, msg.Channel)
+		}
+		return nil
+
+	case pusherSubscriptionError:
+		var subErr struct {
+			Type   string `json:"type"`
+			Error  string `json:"error"`
+			Status int    `json:"status"`
+		}
+		decodePusherData(msg.Data, &subErr)
+		if info, ok := c.route(msg.Channel); ok {
+			info.SetSubscribed(false)
+			info.NotifyError(fmt.Errorf("%s aboneliği reddedildi (%d %s): %s", msg.Channel, subErr.Status, subErr.Type, subErr.Error))
+		}
+		return nil
+	}
+
+	if msg.Channel == "" {
+		return nil
+	}
+	if info, ok := c.route(msg.Channel); ok {
+		c.pool.handler(info, msg)
+	}
+	return nil
+}
+
+func (c *pusherConn) route(channel string) (*ListenerInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info, ok := c.subs[channel]
+	return info, ok
+}
+
+func (c *pusherConn) markAllUnsubscribed() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, info := range c.subs {
+		info.SetSubscribed(false)
+	}
+}
+
+// reportReconnect lets every listener on this socket record the attempt
+func (c *pusherConn) reportReconnect(attempt int, delay time.Duration, err error) {
+	if c.pool.onReconnect == nil {
+		return
+	}
+	c.mu.RLock()
+	infos := make([]*ListenerInfo, 0, len(c.subs))
+	for _, info := range c.subs {
+		infos = append(infos, info)
+	}
+	c.mu.RUnlock()
+
+	for _, info := range infos {
+		c.pool.onReconnect(info, attempt, delay, err)
+	}
+}
+
+// failAll hands a terminal connection error to every listener routed to this socket
+func (c *pusherConn) failAll(err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, info := range c.subs {
+		info.NotifyError(err)
+	}
+}
+
+// flush writes the pending frames in queue order on the live socket. Whoever flushes
+// first writes the frames of other callers too. When disconnected they are dropped,
+// reconnecting resubscribes whatever is routed to the socket by then.
+func (c *pusherConn) flush() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.mu.Lock()
+	frames, ws := c.pending, c.ws
+	c.pending = nil
+	c.mu.Unlock()
+	if ws == nil {
+		return nil
+	}
+	for _, frame := range frames {
+		if err := c.writeLocked(ws, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *pusherConn) write(ws *websocket.Conn, frame string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.writeLocked(ws, frame)
+}
+
+func (c *pusherConn) writeLocked(ws *websocket.Conn, frame string) error {
+	ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return ws.WriteMessage(websocket.TextMessage, []byte(frame))
+}
+
+func (c *pusherConn) isClosed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closed
+}
+
+func (c *pusherConn) close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	ws := c.ws
+	close(c.done)
+	c.mu.Unlock()
+
+	if ws != nil {
+		c.writeMu.Lock()
+		ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		c.writeMu.Unlock()
+		ws.Close()
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Pusher protocol 7 control events
+const (
+	pusherConnectionEstablished = "pusher:connection_established"
+	pusherError                 = "pusher:error"
+	pusherPing                  = "pusher:ping"
+	pusherPong                  = "pusher:pong"
+	pusherSubscriptionSucceeded = "pusher_internal:subscription_succeeded"
+	pusherSubscriptionError     = "pusher:subscription_error"
+
+	pusherPingFrame = `{"event":"pusher:ping","data":{}}`
+	pusherPongFrame = `{"event":"pusher:pong","data":{}}`
+
+	// Used until the server tells us its activity_timeout
+	defaultActivityTimeout = 120 * time.Second
+)
+
+var ErrPusherDeadSocket = errors.New("pusher pong zamanında gelmedi, soket ölü")
+
+type connectionEstablished struct {
+	SocketID        string `json:"socket_id"`
+	ActivityTimeout int    `json:"activity_timeout"`
+}
+
+// PusherError is a pusher:error event or a 4000-4299 close code
+type PusherError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *PusherError) Error() string {
+	return fmt.Sprintf("pusher hatası %d: %s", e.Code, e.Message)
+}
+
+// ReconnectAction tells the connection loop what the Pusher spec expects after an error
+type ReconnectAction int
+
+const (
+	ReconnectWithBackoff ReconnectAction = iota
+	ReconnectImmediately
+	DoNotReconnect
+)
+
+// Action maps the error code ranges defined by the Pusher protocol
+func (e *PusherError) Action() ReconnectAction {
+	switch {
+	case e.Code >= 4000 && e.Code <= 4099:
+		return DoNotReconnect
+	case e.Code >= 4100 && e.Code <= 4199:
+		return ReconnectWithBackoff
+	case e.Code >= 4200 && e.Code <= 4299:
+		return ReconnectImmediately
+	}
+	return ReconnectWithBackoff
+}
+
+// reconnectActionFor classifies any error returned by a connection attempt
+func reconnectActionFor(err error) ReconnectAction {
+	var pusherErr *PusherError
+	if errors.As(err, &pusherErr) {
+		return pusherErr.Action()
+	}
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		return (&PusherError{Code: closeErr.Code, Message: closeErr.Text}).Action()
+	}
+	return ReconnectWithBackoff
+}
+
+// decodePusherData handles Pusher's habit of sending data as a JSON encoded string
+func decodePusherData(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return json.Unmarshal([]byte(str), v)
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReconcileReport summarises one reconciliation pass
+type ReconcileReport struct {
+	Desired     int
+	Started     int
+	Stopped     int
+	Synced      int
+	HandedOff   int
+	Deactivated int64
+}
+
+// StartReconciler runs a first pass synchronously, so listeners come back on
+// startup before the server accepts requests, and keeps reconciling in the background
+func (u *listenUseCase) StartReconciler(ctx context.Context) error {
+	log.Println("Uygulama başlatılıyor: istenen dinleyiciler veritabanıyla eşitleniyor...")
+	ctx, u.cancel = context.WithCancel(ctx)
+
+	if u.coord != nil {
+		// Join the ring first so the first pass already knows the other replicas
+		u.heartbeat(ctx)
+		go u.runCoordinator(ctx)
+	}
+
+	// The loop starts even if the first pass fails, it will catch up on the next tick
+	go u.runReconciler(ctx)
+
+	report, err := u.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("İlk eşitleme tamamlandı: istenen=%d başlatılan=%d durdurulan=%d pasif=%d",
+		report.Desired, report.Started, report.Stopped, report.Deactivated)
+	return nil
+}
+
+func (u *listenUseCase) runReconciler(ctx context.Context) {
+	ticker := time.NewTicker(u.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-u.reconcileNow:
+		}
+
+		report, err := u.Reconcile(ctx)
+		if err != nil {
+			log.Printf("Dinleyici eşitleme hatası: %v", err)
+			continue
+		}
+		if report.Started > 0 || report.Stopped > 0 || report.HandedOff > 0 || report.Deactivated > 0 {
+			log.Printf("Dinleyici eşitlendi: istenen=%d başlatılan=%d durdurulan=%d devredilen=%d güncellenen=%d pasif=%d",
+				report.Desired, report.Started, report.Stopped, report.HandedOff, report.Synced, report.Deactivated)
+		}
+	}
+}
+
+// Reconcile makes ListenerManager match the open requests in Postgres: missing
+// listeners are started, orphans stopped, request sets synced and stale rows deactivated.
+// With a coordinator only the streamers this replica owns are started; the rest are handed off.
+func (u *listenUseCase) Reconcile(ctx context.Context) (ReconcileReport, error) {
+	var report ReconcileReport
+
+	// Anything added to memory after this instant may not be visible in the query yet
+	snapshotAt := time.Now()
+	rows, err := u.repo.GetDesiredListeners(ctx)
+	if err != nil {
+		return report, fmt.Errorf("istenen dinleyiciler alınamadı: %w", err)
+	}
+
+	desired := make(map[string][]domain.DesiredListener)
+	for _, row := range rows {
+		desired[row.Streamer] = append(desired[row.Streamer], row)
+	}
+	report.Desired = len(desired)
+
+	running := ListenerManager.Snapshot()
+	for streamer, requests := range desired {
+		info, exists := running[streamer]
+		if exists && !info.Stopped() {
+			if !u.shouldOwn(streamer) {
+				log.Printf("'%s' başka bir sunucuya devrediliyor", streamer)
+				info.Stop()
+				report.HandedOff++
+				continue
+			}
+			if u.syncRequests(info, requests, snapshotAt) {
+				report.Synced++
+			}
+			if !u.recentlyFailed(requests) && u.launch(info) {
+				report.Started++
+			}
+			continue
+		}
+
+		if u.recentlyFailed(requests) || !u.claim(ctx, streamer) {
+			continue
+		}
+		u.startDesired(streamer, requests)
+		report.Started++
+	}
+
+	for streamer, info := range running {
+		if _, ok := desired[streamer]; ok || info.Stopped() {
+			continue
+		}
+		if info.Status().LastActivity.After(snapshotAt) {
+			continue // created while we were querying
+		}
+		log.Printf("'%s' için açık istek kalmadı, dinleyici durduruluyor", streamer)
+		info.Stop()
+		report.Stopped++
+	}
+
+	deactivated, err := u.repo.DeactivateUnrequestedListeners(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Deactivated = deactivated
+	return report, nil
+}
+
+// startDesired builds a listener for streamer from its stored requests; messages go
+// to the row of the oldest request
+func (u *listenUseCase) startDesired(streamer string, requests []domain.DesiredListener) {
+	info := &ListenerInfo{
+		Username:     streamer,
+		UserRequests: make(map[uuid.UUID]UserRequestInfo),
+		ListenerDBID: requests[0].ListenerID,
+		State:        StatePending,
+		EventChannel: make(chan ChatEvent, u.config.MessageBufferSize),
+		ErrorChannel: make(chan error, 1),
+		StopChannel:  make(chan struct{}),
+		LastActivity: time.Now(),
+	}
+	for _, r := range requests {
+		info.UserRequests[r.UserID] = UserRequestInfo{
+			UserID:      r.UserID,
+			ListenerID:  r.ListenerID,
+			RequestTime: r.RequestTime,
+			EndTime:     r.EndTime,
+			Sinks:       r.Sinks,
+		}
+	}
+	info.recalculateLocked()
+
+	log.Printf("'%s' için %d açık istek bulundu, dinleyici başlatılıyor", streamer, len(requests))
+	ListenerManager.AddListener(streamer, info)
+	u.launch(info)
+}
+
+// syncRequests applies stored end times and drops requests ended elsewhere
+func (u *listenUseCase) syncRequests(info *ListenerInfo, requests []domain.DesiredListener, snapshotAt time.Time) bool {
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	changed := false
+	stored := make(map[uuid.UUID]struct{}, len(requests))
+	for _, r := range requests {
+		stored[r.UserID] = struct{}{}
+		current, ok := info.UserRequests[r.UserID]
+		if ok && current.EndTime.Equal(r.EndTime) && current.ListenerID == r.ListenerID && slices.Equal(current.Sinks, r.Sinks) {
+			continue
+		}
+		info.UserRequests[r.UserID] = UserRequestInfo{
+			UserID:      r.UserID,
+			ListenerID:  r.ListenerID,
+			RequestTime: r.RequestTime,
+			EndTime:     r.EndTime,
+			Sinks:       r.Sinks,
+		}
+		changed = true
+	}
+
+	for userID, request := range info.UserRequests {
+		if _, ok := stored[userID]; ok || request.RequestTime.After(snapshotAt) {
+			continue
+		}
+		delete(info.UserRequests, userID)
+		changed = true
+	}
+
+	if changed {
+		info.recalculateLocked()
+		// Messages must not keep going to the row of a user who left
+		owned := false
+		for _, request := range info.UserRequests {
+			if request.ListenerID == info.ListenerDBID {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			info.ListenerDBID = requests[0].ListenerID
+		}
+	}
+	return changed
+}
+
+// recentlyFailed keeps a listener Pusher refused from being restarted every pass
+func (u *listenUseCase) recentlyFailed(requests []domain.DesiredListener) bool {
+	for _, r := range requests {
+		if r.State != string(StateFailed) || r.StateChangedAt == nil || time.Since(*r.StateChangedAt) > u.config.FailedRetryAfter {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"kick-chat/domain"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SinkEvent is one chat event on its way to the sinks
+type SinkEvent struct {
+	Streamer   string
+	ListenerID uuid.UUID
+	Event      ChatEvent
+	ReceivedAt time.Time
+}
+
+// MessageSink is one output for chat events. Write runs on the sink's own
+// goroutine, so a slow or failing sink only delays itself.
+type MessageSink interface {
+	Write(ctx context.Context, event SinkEvent) error
+}
+
+// SinkFlusher is implemented by sinks that buffer, Flush runs whenever their queue empties
+type SinkFlusher interface {
+	Flush() error
+}
+
+// GlobalSink is implemented by sinks that ignore a listen request's sink selection
+type GlobalSink interface {
+	Global() bool
+}
+
+// SinkSpec configures one sink instance; Name defaults to Type
+type SinkSpec struct {
+	Name     string
+	Type     string
+	Events   []EventType
+	Settings map[string]string
+}
+
+// SinkDeps are the shared services sink factories may use
+type SinkDeps struct {
+	Writer   *MessageWriter
+	Repo     ListenPostgresRepository
+	Exporter *BusExporter
+	Alerts   *AlertEngine
+	Webhooks *WebhookDispatcher
+}
+
+type SinkFactory func(spec SinkSpec, deps SinkDeps) (MessageSink, error)
+
+type SinkRegistry struct {
+	factories map[string]SinkFactory
+}
+
+func NewSinkRegistry() *SinkRegistry {
+	return &SinkRegistry{factories: make(map[string]SinkFactory)}
+}
+
+func (r *SinkRegistry) Register(sinkType string, factory SinkFactory) {
+	r.factories[sinkType] = factory
+}
+
+// DefaultSinkRegistry knows every built in sink type
+func DefaultSinkRegistry() *SinkRegistry {
+	r := NewSinkRegistry()
+	r.Register("console", newConsoleSink)
+	r.Register("postgres", newPostgresSink)
+	r.Register("webhook", newWebhookSink)
+	r.Register("bus", newBusSink)
+	r.Register("file", newFileSink)
+	r.Register("alerts", newAlertSink)
+	r.Register("webhooks", newWebhooksSink)
+	return r
+}
+
+type SinkStats struct {
+	Type      string `json:"type"`
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"`
+	Failed    int64  `json:"failed"`
+	Pending   int    `json:"pending"`
+}
+
+// sinkRunner owns one sink's buffer and goroutine
+type sinkRunner struct {
+	name    string
+	spec    SinkSpec
+	sink    MessageSink
+	events  map[EventType]struct{}
+	global  bool
+	queue   chan SinkEvent
+	timeout time.Duration
+
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+	lastLog   time.Time
+}
+
+func (r *sinkRunner) accepts(eventType EventType) bool {
+	if len(r.events) == 0 {
+		return true
+	}
+	_, ok := r.events[eventType]
+	return ok
+}
+
+func (r *sinkRunner) enqueue(event SinkEvent) {
+	select {
+	case r.queue <- event:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+func (r *sinkRunner) run(ctx context.Context) {
+	flusher, _ := r.sink.(SinkFlusher)
+	for {
+		select {
+		case <-ctx.Done():
+			for len(r.queue) > 0 {
+				r.write(<-r.queue)
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return
+		case event := <-r.queue:
+			r.write(event)
+			if flusher != nil && len(r.queue) == 0 {
+				if err := flusher.Flush(); err != nil {
+					r.logError(err)
+				}
+			}
+		}
+	}
+}
+
+// write isolates the pipeline from the sink: errors are counted, panics recovered
+func (r *sinkRunner) write(event SinkEvent) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.failed.Add(1)
+			r.logError(fmt.Errorf("panic: %v", rec))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	if err := r.sink.Write(ctx, event); err != nil {
+		r.failed.Add(1)
+		r.logError(err)
+		return
+	}
+	r.delivered.Add(1)
+}
+
+// logError keeps a broken sink from flooding the log, at most one line per 10s
+func (r *sinkRunner) logError(err error) {
+	if time.Since(r.lastLog) < 10*time.Second {
+		return
+	}
+	r.lastLog = time.Now()
+	log.Printf("'%s' çıktısına yazılamadı (toplam hata %d): %v", r.name, r.failed.Load(), err)
+}
+
+// SinkPipeline dispatches every event to the sinks a listener selected
+type SinkPipeline struct {
+	runners []*sinkRunner
+	byName  map[string]*sinkRunner
+}
+
+func NewSinkPipeline(registry *SinkRegistry, specs []SinkSpec, deps SinkDeps, config *Config) (*SinkPipeline, error) {
+	p := &SinkPipeline{byName: make(map[string]*sinkRunner, len(specs))}
+	for _, spec := range specs {
+		if spec.Name == "" {
+			spec.Name = spec.Type
+		}
+		if _, dup := p.byName[spec.Name]; dup {
+			return nil, fmt.Errorf("çıktı adı iki kez tanımlanmış: %s", spec.Name)
+		}
+		factory, ok := registry.factories[spec.Type]
+		if !ok {
+			return nil, fmt.Errorf("bilinmeyen çıktı tipi: %s", spec.Type)
+		}
+		sink, err := factory(spec, deps)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' çıktısı oluşturulamadı: %w", spec.Name, err)
+		}
+
+		runner := &sinkRunner{
+			name:    spec.Name,
+			spec:    spec,
+			sink:    sink,
+			queue:   make(chan SinkEvent, config.SinkBufferSize),
+			timeout: config.SinkWriteTimeout,
+		}
+		if g, ok := sink.(GlobalSink); ok {
+			runner.global = g.Global()
+		}
+		if len(spec.Events) > 0 {
+			runner.events = make(map[EventType]struct{}, len(spec.Events))
+			for _, eventType := range spec.Events {
+				runner.events[eventType] = struct{}{}
+			}
+		}
+		p.runners = append(p.runners, runner)
+		p.byName[spec.Name] = runner
+	}
+	return p, nil
+}
+
+// Run blocks until ctx is cancelled and every sink has drained its queue
+func (p *SinkPipeline) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, runner := range p.runners {
+		wg.Add(1)
+		go func(runner *sinkRunner) {
+			defer wg.Done()
+			runner.run(ctx)
+		}(runner)
+	}
+	wg.Wait()
+}
+
+// Validate rejects sink names a listen request may not select
+func (p *SinkPipeline) Validate(names []string) error {
+	for _, name := range names {
+		if _, ok := p.byName[name]; !ok {
+			return fmt.Errorf("%w: bilinmeyen çıktı: %s (tanımlı: %v)", domain.ErrInvalidInput, name, p.Names())
+		}
+	}
+	return nil
+}
+
+func (p *SinkPipeline) Names() []string {
+	names := make([]string, 0, len(p.runners))
+	for _, runner := range p.runners {
+		names = append(names, runner.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dispatch never blocks; allowed nil means every sink
+func (p *SinkPipeline) Dispatch(event SinkEvent, allowed map[string]struct{}) {
+	eventType := event.Event.EventType()
+	for _, runner := range p.runners {
+		if allowed != nil && !runner.global {
+			if _, ok := allowed[runner.name]; !ok {
+				continue
+			}
+		}
+		if runner.accepts(eventType) {
+			runner.enqueue(event)
+		}
+	}
+}
+
+func (p *SinkPipeline) Stats() map[string]SinkStats {
+	stats := make(map[string]SinkStats, len(p.runners))
+	for _, runner := range p.runners {
+		stats[runner.name] = SinkStats{
+			Type:      runner.spec.Type,
+			Delivered: runner.delivered.Load(),
+			Dropped:   runner.dropped.Load(),
+			Failed:    runner.failed.Load(),
+			Pending:   len(runner.queue),
+		}
+	}
+	return stats
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kick-chat/domain"
+	"kick-chat/utils"
+	"net/http"
+	"os"
+
+	"github.com/logrusorgru/aurora"
+)
+
+func exportEvent(event SinkEvent) ([]byte, error) {
+	return json.Marshal(exportedEvent{
+		Streamer:   event.Streamer,
+		Type:       string(event.Event.EventType()),
+		ReceivedAt: event.ReceivedAt,
+		Data:       event.Event,
+	})
+}
+
+// consoleSink is the colored terminal output the listener always had
+type consoleSink struct{}
+
+func newConsoleSink(SinkSpec, SinkDeps) (MessageSink, error) {
+	return consoleSink{}, nil
+}
+
+func (consoleSink) Write(_ context.Context, event SinkEvent) error {
+	data, ok := event.Event.(*Data)
+	if !ok {
+		fmt.Print(aurora.Colorize(
+			fmt.Sprintf("📣 %s:%s\n", event.Streamer, describeEvent(event.Event)),
+			aurora.MagentaFg,
+		))
+		return nil
+	}
+
+	fmt.Print(aurora.Colorize(
+		fmt.Sprintf("💬 %s:%s:%s\n", event.Streamer, data.Sender.Username, data.Content),
+		utils.GetColorFromHex(data.Sender.Identity.Color),
+	))
+	for _, link := range linkRegex.FindAllString(data.Content, -1) {
+		fmt.Print(aurora.Colorize(
+			fmt.Sprintf("🔗 [%s] %s LINK: %s\n", event.Streamer, data.Sender.Username, link),
+			aurora.YellowFg|aurora.BoldFm,
+		))
+	}
+	return nil
+}
+
+// postgresSink stores messages through the batched MessageWriter and other events directly
+type postgresSink struct {
+	writer *MessageWriter
+	repo   ListenPostgresRepository
+}
+
+func newPostgresSink(_ SinkSpec, deps SinkDeps) (MessageSink, error) {
+	return &postgresSink{writer: deps.Writer, repo: deps.Repo}, nil
+}
+
+// Global keeps storage on whatever a request selects: history, search, resume
+// and export are shared by every user on the streamer
+func (s *postgresSink) Global() bool {
+	return true
+}
+
+func (s *postgresSink) Write(_ context.Context, event SinkEvent) error {
+	data, ok := event.Event.(*Data)
+	if !ok {
+		payload, err := json.Marshal(event.Event)
+		if err != nil {
+			return err
+		}
+		return s.repo.InsertChatEvent(event.ListenerID, string(event.Event.EventType()), eventID(event.Event), payload, event.ReceivedAt)
+	}
+
+	extractedLinks := linkRegex.FindAllString(data.Content, -1)
+	timestamp := data.Timestamp
+	if timestamp.IsZero() {
+		timestamp = event.ReceivedAt
+	}
+
+	// Drops are counted and reported by the writer itself
+	s.writer.Enqueue(domain.ChatMessage{
+		ListenerID:     event.ListenerID,
+		KickMessageID:  data.ID,
+		SenderID:       data.Sender.ID,
+		SenderUsername: data.Sender.Username,
+		SenderColor:    data.Sender.Identity.Color,
+		Content:        data.Content,
+		Timestamp:      timestamp,
+		HasLink:        len(extractedLinks) > 0,
+		ExtractedLinks: extractedLinks,
+	})
+	return nil
+}
+
+// webhookSink POSTs every event as JSON to settings["url"]
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(spec SinkSpec, _ SinkDeps) (MessageSink, error) {
+	url := spec.Settings["url"]
+	if url == "" {
+		return nil, errors.New("webhook için url gerekli")
+	}
+	return &webhookSink{url: url, client: &http.Client{}}, nil
+}
+
+func (s *webhookSink) Write(ctx context.Context, event SinkEvent) error {
+	body, err := exportEvent(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %d döndü", resp.StatusCode)
+	}
+	return nil
+}
+
+// busSink hands events to the RabbitMQ exporter, which has its own outbox
+type busSink struct {
+	exporter *BusExporter
+}
+
+func newBusSink(_ SinkSpec, deps SinkDeps) (MessageSink, error) {
+	if deps.Exporter == nil {
+		return nil, errors.New("bus çıktısı için rabbitmq açık olmalı")
+	}
+	return &busSink{exporter: deps.Exporter}, nil
+}
+
+func (s *busSink) Write(_ context.Context, event SinkEvent) error {
+	s.exporter.Publish(event.Streamer, event.Event)
+	return nil
+}
+
+// fileSink appends one JSON line per event to settings["path"]
+type fileSink struct {
+	file *os.File
+	buf  *bufio.Writer
+}
+
+func newFileSink(spec SinkSpec, _ SinkDeps) (MessageSink, error) {
+	path := spec.Settings["path"]
+	if path == "" {
+		return nil, errors.New("file için path gerekli")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file, buf: bufio.NewWriter(file)}, nil
+}
+
+func (s *fileSink) Write(_ context.Context, event SinkEvent) error {
+	line, err := exportEvent(event)
+	if err != nil {
+		return err
+	}
+	if _, err := s.buf.Write(line); err != nil {
+		return err
+	}
+	return s.buf.WriteByte('\n')
+}
+
+func (s *fileSink) Flush() error {
+	return s.buf.Flush()
+}
+
+func newAlertSink(_ SinkSpec, deps SinkDeps) (MessageSink, error) {
+	if deps.Alerts == nil {
+		return nil, errors.New("alarm motoru tanımlı değil")
+	}
+	return deps.Alerts, nil
+}
+
+// newWebhooksSink feeds the user registered webhook queue, unlike "webhook" which posts to one fixed url
+func newWebhooksSink(_ SinkSpec, deps SinkDeps) (MessageSink, error) {
+	if deps.Webhooks == nil {
+		return nil, errors.New("webhook dağıtıcısı tanımlı değil")
+	}
+	return deps.Webhooks, nil
+}
+
+// DefaultSinkSpecs is used when the deployment configures no sinks
+func DefaultSinkSpecs(withBus bool) []SinkSpec {
+	specs := []SinkSpec{{Type: "console"}, {Type: "postgres"}, {Type: "alerts"}, {Type: "webhooks"}}
+	if withBus {
+		specs = append(specs, SinkSpec{Type: "bus"})
+	}
+	return specs
+}
+
+// ParseSinkEvents converts configured event names, unknown ones are rejected
+func ParseSinkEvents(names []string) ([]EventType, error) {
+	types := make([]EventType, 0, len(names))
+	for _, name := range names {
+		eventType := EventType(name)
+		if _, ok := eventTypeFactories[eventType]; !ok {
+			return nil, fmt.Errorf("bilinmeyen olay tipi: %s", name)
+		}
+		types = append(types, eventType)
+	}
+	return types, nil
+}
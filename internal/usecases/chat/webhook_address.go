@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errWebhookAddress means a webhook points into our own network. Any user can
+// register an endpoint and read back status codes, so it would be a port scanner.
+var errWebhookAddress = errors.New("webhook adresi yerel ya da özel bir ağa çıkıyor")
+
+// publicWebhookAddr rejects loopback, private, link-local (cloud metadata),
+// multicast and unspecified addresses
+func publicWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// checkWebhookHost resolves host at registration; every address it has must be public
+func checkWebhookHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicWebhookAddr(addr) {
+			return errWebhookAddress
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("webhook adresi çözümlenemedi: %s", host)
+	}
+	for _, addr := range addrs {
+		if !publicWebhookAddr(addr) {
+			return errWebhookAddress
+		}
+	}
+	return nil
+}
+
+// webhookDialControl checks the address actually dialled, DNS may have changed
+// since the endpoint was registered
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errWebhookAddress, address)
+	}
+	if !publicWebhookAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errWebhookAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// newWebhookTransport dials only public addresses and never through a proxy,
+// a proxy would be dialled instead of the receiver and skip the check
+func newWebhookTransport(timeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   webhookDialControl,
+	}).DialContext
+	return transport
+}
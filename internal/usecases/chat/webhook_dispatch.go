@@ -0,0 +1,543 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kick-chat/domain"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookEventLink  = "link"
+	webhookEventAlert = "alert"
+)
+
+// DefaultWebhookEvents is used when an endpoint is registered without an event list
+var DefaultWebhookEvents = []string{string(EventTypeMessage), webhookEventLink, webhookEventAlert}
+
+type WebhookDispatcherRepository interface {
+	GetEnabledWebhookEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error)
+	EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	MarkWebhookDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int) error
+	MarkWebhookFailed(ctx context.Context, deliveryID uuid.UUID, statusCode *int, lastError string, nextAttempt *time.Time) error
+	DeferWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, at time.Time) error
+	GetStreamerChannel(ctx context.Context, username string) (*domain.ChannelInfo, error)
+}
+
+// webhookPayload is the signed JSON body endpoints receive; ID is shared by
+// every endpoint the event went to so receivers can deduplicate retries
+type webhookPayload struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	Streamer   string      `json:"streamer"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type WebhookStats struct {
+	Enqueued  int64 `json:"enqueued"`
+	Delivered int64 `json:"delivered"`
+	Failed    int64 `json:"failed"`
+	Dead      int64 `json:"dead"`
+	Deferred  int64 `json:"deferred"`
+	Endpoints int   `json:"endpoints"`
+}
+
+// WebhookDispatcher turns chat events and alerts into rows of the webhook
+// queue and delivers them. The queue lives in Postgres, so deliveries survive
+// restarts and any replica may send them.
+type WebhookDispatcher struct {
+	repo      WebhookDispatcherRepository
+	config    *Config
+	client    *http.Client
+	retry     *Backoff
+	limiter   *webhookLimiter
+	endpoints atomic.Pointer[[]domain.WebhookEndpoint]
+	wake      chan struct{}
+
+	picMu sync.Mutex
+	pics  map[string]profilePic
+
+	enqueued  atomic.Int64
+	delivered atomic.Int64
+	failed    atomic.Int64
+	dead      atomic.Int64
+	deferred  atomic.Int64
+}
+
+func NewWebhookDispatcher(repo WebhookDispatcherRepository, config *Config) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		repo:   repo,
+		config: config,
+		client: &http.Client{
+			Timeout:   config.WebhookTimeout,
+			Transport: newWebhookTransport(config.WebhookTimeout),
+			// A redirect would resend the signed body somewhere the user never registered
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		retry: &Backoff{
+			Initial:    config.WebhookRetryBase,
+			Max:        config.WebhookRetryMax,
+			Multiplier: 2,
+			Jitter:     0.2,
+		},
+		limiter: newWebhookLimiter(),
+		wake:    make(chan struct{}, 1),
+		pics:    make(map[string]profilePic),
+	}
+	d.endpoints.Store(&[]domain.WebhookEndpoint{})
+	return d
+}
+
+// Reload refreshes the endpoint list; endpoints added on another replica arrive on the next refresh
+func (d *WebhookDispatcher) Reload(ctx context.Context) error {
+	endpoints, err := d.repo.GetEnabledWebhookEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+	d.endpoints.Store(&endpoints)
+	return nil
+}
+
+// Run reloads endpoints periodically and sends due deliveries with WebhookWorkers goroutines
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	if err := d.Reload(ctx); err != nil {
+		log.Printf("Webhooklar yüklenemedi: %v", err)
+	}
+
+	jobs := make(chan []domain.WebhookDelivery)
+	var wg sync.WaitGroup
+	for i := 0; i < d.config.WebhookWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				d.send(batch)
+			}
+		}()
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	refresh := time.NewTicker(d.config.WebhookRefresh)
+	defer refresh.Stop()
+	poll := time.NewTicker(d.config.WebhookPollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-refresh.C:
+			if err := d.Reload(ctx); err != nil {
+				log.Printf("Webhooklar yenilenemedi: %v", err)
+			}
+		case <-poll.C:
+		case <-d.wake:
+		}
+
+		// Keep claiming while full batches come back, a backlog drains without waiting for ticks
+		for ctx.Err() == nil {
+			// Claimed rows stay hidden for a couple of timeouts, a crashed worker only delays them
+			deliveries, err := d.repo.ClaimWebhookDeliveries(ctx, d.config.WebhookClaimLimit, 2*d.config.WebhookTimeout)
+			if err != nil {
+				log.Printf("Webhook kuyruğu okunamadı: %v", err)
+				break
+			}
+			for _, batch := range batchDeliveries(deliveries) {
+				select {
+				case jobs <- batch:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(deliveries) < d.config.WebhookClaimLimit {
+				break
+			}
+		}
+	}
+}
+
+func (d *WebhookDispatcher) nudge() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Global keeps webhooks on regardless of the listen request's sink selection,
+// each endpoint already picks its own events
+func (d *WebhookDispatcher) Global() bool {
+	return true
+}
+
+// Write queues the event, plus one link event per URL in a message, for every
+// endpoint whose owner is listening to the streamer
+func (d *WebhookDispatcher) Write(ctx context.Context, event SinkEvent) error {
+	endpoints := *d.endpoints.Load()
+	if len(endpoints) == 0 {
+		return nil
+	}
+	listener, ok := ListenerManager.GetListener(event.Streamer)
+	if !ok {
+		return nil
+	}
+	interested := func(e domain.WebhookEndpoint) bool {
+		return listener.HasUserRequest(e.UserID)
+	}
+
+	eventType := string(event.Event.EventType())
+	deliveries, err := d.deliveries(endpoints, interested, eventType, event.Streamer, event.ReceivedAt, event.Event)
+	if err != nil {
+		return err
+	}
+	if msg, ok := event.Event.(*Data); ok {
+		for _, link := range linkRegex.FindAllString(msg.Content, -1) {
+			linked, err := d.deliveries(endpoints, interested, webhookEventLink, event.Streamer, event.ReceivedAt, busLink{
+				MessageID: msg.ID,
+				Sender:    msg.Sender.Username,
+				URL:       link,
+				Content:   msg.Content,
+			})
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, linked...)
+		}
+	}
+	return d.enqueue(ctx, deliveries)
+}
+
+// OnAlert is registered on the AlertEngine, alerts go to the rule owner's endpoints only
+func (d *WebhookDispatcher) OnAlert(alert domain.Alert) {
+	endpoints := *d.endpoints.Load()
+	if len(endpoints) == 0 {
+		return
+	}
+	deliveries, err := d.deliveries(endpoints, func(e domain.WebhookEndpoint) bool {
+		return e.UserID == alert.UserID
+	}, webhookEventAlert, alert.Streamer, alert.CreatedAt, alert)
+	if err != nil {
+		log.Printf("Alarm webhooku hazırlanamadı: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.WebhookTimeout)
+	defer cancel()
+	if err := d.enqueue(ctx, deliveries); err != nil {
+		log.Printf("Alarm webhooku kuyruğa yazılamadı: %v", err)
+	}
+}
+
+// batchDeliveries keeps generic deliveries one per request and groups formatted ones
+// per endpoint, up to what the platform accepts in a single post
+func batchDeliveries(deliveries []domain.WebhookDelivery) [][]domain.WebhookDelivery {
+	var batches [][]domain.WebhookDelivery
+	open := make(map[uuid.UUID]int)
+	for _, delivery := range deliveries {
+		formatter, ok := webhookFormatters[delivery.Format]
+		if !ok {
+			batches = append(batches, []domain.WebhookDelivery{delivery})
+			continue
+		}
+		if i, ok := open[delivery.EndpointID]; ok && len(batches[i]) < formatter.maxBatch() {
+			batches[i] = append(batches[i], delivery)
+			continue
+		}
+		open[delivery.EndpointID] = len(batches)
+		batches = append(batches, []domain.WebhookDelivery{delivery})
+	}
+	return batches
+}
+
+// deliveries builds one queue row per matching endpoint, all sharing a single event id.
+// Formatted endpoints wait WebhookBatchWindow so a burst goes out as one post.
+func (d *WebhookDispatcher) deliveries(endpoints []domain.WebhookEndpoint, interested func(domain.WebhookEndpoint) bool, eventType, streamer string, at time.Time, data interface{}) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	var payload []byte
+	eventID := uuid.New()
+	batchAt := time.Now().Add(d.config.WebhookBatchWindow)
+
+	for _, endpoint := range endpoints {
+		if !slices.Contains(endpoint.Events, eventType) {
+			continue
+		}
+		if endpoint.Streamer != nil && !strings.EqualFold(*endpoint.Streamer, streamer) {
+			continue
+		}
+		if !interested(endpoint) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = json.Marshal(webhookPayload{ID: eventID, Type: eventType, Streamer: streamer, OccurredAt: at, Data: data})
+			if err != nil {
+				return nil, err
+			}
+		}
+		delivery := domain.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			EventID:    eventID,
+			EventType:  eventType,
+			Payload:    payload,
+		}
+		if _, ok := webhookFormatters[endpoint.Format]; ok {
+			delivery.NextAttemptAt = &batchAt
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (d *WebhookDispatcher) enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.repo.EnqueueWebhookDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	d.enqueued.Add(int64(len(deliveries)))
+	d.nudge()
+	return nil
+}
+
+// send makes one attempt for a batch of deliveries to the same endpoint; failures are
+// rescheduled with backoff until WebhookMaxAttempts, then dead-lettered
+func (d *WebhookDispatcher) send(batch []domain.WebhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*d.config.WebhookTimeout)
+	defer cancel()
+
+	lead := batch[0]
+	if wait := d.limiter.reserve(lead.EndpointID, lead.RateLimitPerMin); wait > 0 {
+		d.postpone(ctx, batch, wait)
+		return
+	}
+
+	body, n, err := d.render(ctx, batch)
+	if err != nil {
+		// Rendering fails the same way every time, retrying would not help
+		for _, delivery := range batch {
+			d.fail(ctx, delivery, 0, err, true)
+		}
+		return
+	}
+	if n < len(batch) {
+		// The rest did not fit in one post, they lead the next one
+		d.postpone(ctx, batch[n:], 0)
+		batch = batch[:n]
+	}
+
+	statusCode, retryAfter, err := d.post(ctx, lead, body)
+	if statusCode == http.StatusTooManyRequests && retryAfter > 0 {
+		d.postpone(ctx, batch, retryAfter)
+		return
+	}
+	// An endpoint now resolving into our network stays that way for the retries
+	blocked := errors.Is(err, errWebhookAddress)
+	for _, delivery := range batch {
+		if err != nil {
+			d.fail(ctx, delivery, statusCode, err, blocked)
+			continue
+		}
+		d.delivered.Add(1)
+		if err := d.repo.MarkWebhookDelivered(ctx, delivery.ID, statusCode); err != nil {
+			log.Printf("Webhook teslimatı %s güncellenemedi: %v", delivery.ID, err)
+		}
+	}
+}
+
+// postpone pushes deliveries back without counting an attempt, for rate limits ours or theirs
+func (d *WebhookDispatcher) postpone(ctx context.Context, batch []domain.WebhookDelivery, wait time.Duration) {
+	at := time.Now().Add(wait)
+	for _, delivery := range batch {
+		d.deferred.Add(1)
+		if err := d.repo.DeferWebhookDelivery(ctx, delivery.ID, at); err != nil {
+			log.Printf("Webhook teslimatı %s ertelenemedi: %v", delivery.ID, err)
+		}
+	}
+}
+
+func (d *WebhookDispatcher) fail(ctx context.Context, delivery domain.WebhookDelivery, statusCode int, cause error, permanent bool) {
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	attempts := delivery.Attempts + 1
+	var next *time.Time
+	if !permanent && attempts < d.config.WebhookMaxAttempts {
+		at := time.Now().Add(d.retry.Delay(attempts))
+		next = &at
+		d.failed.Add(1)
+	} else {
+		d.dead.Add(1)
+		log.Printf("Webhook teslimatı %s %d denemeden sonra bırakıldı: %v", delivery.ID, attempts, cause)
+	}
+	if err := d.repo.MarkWebhookFailed(ctx, delivery.ID, code, cause.Error(), next); err != nil {
+		log.Printf("Webhook teslimatı %s güncellenemedi: %v", delivery.ID, err)
+	}
+}
+
+// render returns the stored payload for generic endpoints and the platform post
+// otherwise, plus how many deliveries of the batch the body holds
+func (d *WebhookDispatcher) render(ctx context.Context, batch []domain.WebhookDelivery) ([]byte, int, error) {
+	formatter, ok := webhookFormatters[batch[0].Format]
+	if !ok {
+		return batch[0].Payload, 1, nil
+	}
+
+	notices := make([]webhookNotice, 0, len(batch))
+	for _, delivery := range batch {
+		notice, err := decodeNotice(delivery.Payload)
+		if err != nil {
+			return nil, 0, fmt.Errorf("webhook olayı okunamadı: %w", err)
+		}
+		notice.ProfilePic = d.profilePic(ctx, notice.Streamer)
+		notices = append(notices, notice)
+	}
+	return formatter.render(notices)
+}
+
+type profilePic struct {
+	url       string
+	fetchedAt time.Time
+}
+
+// profilePic reads streamers.profile_pic through a small cache, a missing picture is cached too
+func (d *WebhookDispatcher) profilePic(ctx context.Context, streamer string) string {
+	d.picMu.Lock()
+	cached, ok := d.pics[streamer]
+	d.picMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < d.config.WebhookProfileCacheTTL {
+		return cached.url
+	}
+
+	var url string
+	if info, err := d.repo.GetStreamerChannel(ctx, streamer); err == nil {
+		url = info.ProfilePic
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return cached.url
+	}
+
+	d.picMu.Lock()
+	d.pics[streamer] = profilePic{url: url, fetchedAt: time.Now()}
+	d.picMu.Unlock()
+	return url
+}
+
+// post signs timestamp.body with the endpoint secret, receivers recompute it and
+// reject stale timestamps to stop replays. retryAfter is set when the receiver asked us to slow down.
+func (d *WebhookDispatcher) post(ctx context.Context, delivery domain.WebhookDelivery, body []byte) (int, time.Duration, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kick-chat-webhooks")
+	req.Header.Set("X-Kick-Event", delivery.EventType)
+	req.Header.Set("X-Kick-Delivery", delivery.ID.String())
+	req.Header.Set("X-Kick-Timestamp", timestamp)
+	req.Header.Set("X-Kick-Signature", "sha256="+signWebhook(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("webhook %d döndü", resp.StatusCode)
+	}
+	return resp.StatusCode, 0, nil
+}
+
+// parseRetryAfter reads the delay-seconds form, Discord sends fractional seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *WebhookDispatcher) Stats() WebhookStats {
+	return WebhookStats{
+		Enqueued:  d.enqueued.Load(),
+		Delivered: d.delivered.Load(),
+		Failed:    d.failed.Load(),
+		Dead:      d.dead.Load(),
+		Deferred:  d.deferred.Load(),
+		Endpoints: len(*d.endpoints.Load()),
+	}
+}
+
+// webhookLimiter is a token bucket per endpoint, refilled at perMinute/60 tokens a second.
+// Limits are per replica; with N replicas an endpoint can see up to N times its rate.
+type webhookLimiter struct {
+	mu      sync.Mutex
+	buckets map[uuid.UUID]*webhookBucket
+}
+
+type webhookBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newWebhookLimiter() *webhookLimiter {
+	return &webhookLimiter{buckets: make(map[uuid.UUID]*webhookBucket)}
+}
+
+// reserve takes a token, or returns how long until one is available
+func (l *webhookLimiter) reserve(endpointID uuid.UUID, perMinute int) time.Duration {
+	if perMinute <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	capacity := float64(perMinute)
+	rate := capacity / 60
+
+	bucket, ok := l.buckets[endpointID]
+	if !ok {
+		bucket = &webhookBucket{tokens: capacity, last: now}
+		l.buckets[endpointID] = bucket
+	}
+	bucket.tokens = min(capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+	return time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"kick-chat/domain"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// Kick green, used when a sender has no identity color
+	defaultNoticeColor = 0x53FC18
+	alertNoticeColor   = 0xE74C3C
+)
+
+// webhookFormatter renders queued events as one post for a chat platform's incoming webhook
+type webhookFormatter interface {
+	// render returns the post and how many of the notices, from the front, it holds;
+	// the rest did not fit and go out in a later post
+	render(notices []webhookNotice) ([]byte, int, error)
+	// maxBatch is how many events fit in one post at most
+	maxBatch() int
+}
+
+var webhookFormatters = map[string]webhookFormatter{
+	domain.WebhookFormatDiscord: discordFormatter{},
+	domain.WebhookFormatSlack:   slackFormatter{},
+}
+
+func validWebhookFormat(format string) bool {
+	if format == domain.WebhookFormatJSON {
+		return true
+	}
+	_, ok := webhookFormatters[format]
+	return ok
+}
+
+// webhookNotice is the platform neutral view of one queued event
+type webhookNotice struct {
+	Type       string
+	Streamer   string
+	ProfilePic string
+	Sender     string
+	Color      int
+	Content    string
+	Matched    string
+	Links      []string
+	OccurredAt time.Time
+}
+
+// decodeNotice reads a stored webhookPayload back; the queue keeps the generic
+// JSON so the delivery log looks the same whatever the endpoint's format
+func decodeNotice(payload []byte) (webhookNotice, error) {
+	var raw struct {
+		Type       string          `json:"type"`
+		Streamer   string          `json:"streamer"`
+		OccurredAt time.Time       `json:"occurred_at"`
+		Data       json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return webhookNotice{}, err
+	}
+	notice := webhookNotice{
+		Type:       raw.Type,
+		Streamer:   raw.Streamer,
+		Color:      defaultNoticeColor,
+		OccurredAt: raw.OccurredAt,
+	}
+
+	switch raw.Type {
+	case string(EventTypeMessage):
+		var msg Data
+		if err := json.Unmarshal(raw.Data, &msg); err != nil {
+			return notice, err
+		}
+		notice.Sender = msg.Sender.Username
+		notice.Color = parseNoticeColor(msg.Sender.Identity.Color)
+		notice.Content = msg.Content
+		notice.Links = linkRegex.FindAllString(msg.Content, -1)
+
+	case webhookEventLink:
+		var link busLink
+		if err := json.Unmarshal(raw.Data, &link); err != nil {
+			return notice, err
+		}
+		notice.Sender = link.Sender
+		notice.Content = link.Content
+		notice.Links = []string{link.URL}
+
+	case webhookEventAlert:
+		var alert domain.Alert
+		if err := json.Unmarshal(raw.Data, &alert); err != nil {
+			return notice, err
+		}
+		notice.Sender = alert.SenderUsername
+		notice.Color = alertNoticeColor
+		notice.Content = alert.Content
+		notice.Matched = alert.Matched
+		notice.Links = linkRegex.FindAllString(alert.Content, -1)
+
+	default:
+		factory, ok := eventTypeFactories[EventType(raw.Type)]
+		if !ok {
+			return notice, fmt.Errorf("bilinmeyen olay tipi: %s", raw.Type)
+		}
+		event := factory()
+		if err := json.Unmarshal(raw.Data, event); err != nil {
+			return notice, err
+		}
+		notice.Content = describeEvent(event)
+	}
+	return notice, nil
+}
+
+func parseNoticeColor(hex string) int {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return defaultNoticeColor
+	}
+	color, err := strconv.ParseInt(hex, 16, 32)
+	if err != nil {
+		return defaultNoticeColor
+	}
+	return int(color)
+}
+
+func (n webhookNotice) title() string {
+	switch n.Type {
+	case webhookEventAlert:
+		return "🔔 Alarm: " + n.Matched
+	case webhookEventLink:
+		return "🔗 " + n.Sender + " link paylaştı"
+	case string(EventTypeMessage):
+		return n.Sender
+	}
+	return "📣 " + n.Type
+}
+
+func kickChannelURL(streamer string) string {
+	return "https://kick.com/" + streamer
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// Discord rejects a message whose embeds hold more than 6000 characters in total
+const discordEmbedCharLimit = 6000
+
+// discordFormatter renders one embed per event. Discord allows 10 embeds per message;
+// the truncation limits keep a single embed small and render stops at the character limit.
+type discordFormatter struct{}
+
+type discordEmbed struct {
+	Author      discordAuthor  `json:"author"`
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordAuthor struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type discordField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (discordFormatter) maxBatch() int {
+	return 10
+}
+
+// chars counts what Discord counts towards the 6000 character limit
+func (e discordEmbed) chars() int {
+	total := utf8.RuneCountInString(e.Author.Name) + utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	for _, field := range e.Fields {
+		total += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return total
+}
+
+func (discordFormatter) render(notices []webhookNotice) ([]byte, int, error) {
+	embeds := make([]discordEmbed, 0, len(notices))
+	total := 0
+	for _, n := range notices {
+		embed := discordEmbed{
+			Author:      discordAuthor{Name: n.Streamer, URL: kickChannelURL(n.Streamer), IconURL: n.ProfilePic},
+			Title:       truncateRunes(n.title(), 200),
+			Description: truncateRunes(n.Content, 400),
+			Color:       n.Color,
+			Timestamp:   n.OccurredAt.UTC().Format(time.RFC3339),
+		}
+		if len(n.Links) > 0 {
+			embed.Fields = []discordField{{Name: "Linkler", Value: truncateRunes(strings.Join(n.Links, "\n"), 150)}}
+		}
+		// A single embed is far below the limit, so the first one always fits
+		if total += embed.chars(); total > discordEmbedCharLimit {
+			break
+		}
+		embeds = append(embeds, embed)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"username": "Kick Chat",
+		"embeds":   embeds,
+		// Chat content must never ping the server
+		"allowed_mentions": map[string][]string{"parse": {}},
+	})
+	return body, len(embeds), err
+}
+
+// slackFormatter renders Block Kit blocks inside one colored attachment per event,
+// attachments being the only place Slack still shows a color bar
+type slackFormatter struct{}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	AltText  string `json:"alt_text,omitempty"`
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (slackFormatter) maxBatch() int {
+	return 10
+}
+
+func (slackFormatter) render(notices []webhookNotice) ([]byte, int, error) {
+	attachments := make([]slackAttachment, 0, len(notices))
+	for _, n := range notices {
+		header := []slackElement{{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*<%s|%s>* · <!date^%d^{time}|%s>", kickChannelURL(n.Streamer), slackEscaper.Replace(n.Streamer),
+				n.OccurredAt.Unix(), n.OccurredAt.UTC().Format(time.RFC3339)),
+		}}
+		if n.ProfilePic != "" {
+			header = append([]slackElement{{Type: "image", ImageURL: n.ProfilePic, AltText: n.Streamer}}, header...)
+		}
+
+		blocks := []slackBlock{
+			{Type: "context", Elements: header},
+			{Type: "section", Text: &slackText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*%s*\n%s", slackEscaper.Replace(truncateRunes(n.title(), 200)), slackEscaper.Replace(truncateRunes(n.Content, 2000))),
+			}},
+		}
+		if len(n.Links) > 0 {
+			links := make([]string, 0, len(n.Links))
+			for _, link := range n.Links {
+				links = append(links, "<"+slackEscaper.Replace(link)+">")
+			}
+			blocks = append(blocks, slackBlock{Type: "context", Elements: []slackElement{{
+				Type: "mrkdwn",
+				Text: "🔗 " + strings.Join(links, " "),
+			}}})
+		}
+		attachments = append(attachments, slackAttachment{Color: fmt.Sprintf("#%06X", n.Color), Blocks: blocks})
+	}
+
+	fallback := fmt.Sprintf("%s: %s", notices[0].Streamer, notices[0].title())
+	if len(notices) > 1 {
+		fallback = fmt.Sprintf("%d yeni olay", len(notices))
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"text":        fallback,
+		"attachments": attachments,
+	})
+	return body, len(notices), err
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// worstCaseNotice fills every field Discord counts past its truncation limit
+func worstCaseNotice() webhookNotice {
+	links := make([]string, 10)
+	for i := range links {
+		links[i] = "https://example.com/" + strings.Repeat("x", 40)
+	}
+	return webhookNotice{
+		Type:       webhookEventAlert,
+		Streamer:   strings.Repeat("s", 25),
+		Sender:     strings.Repeat("u", 25),
+		Matched:    strings.Repeat("ğ", 300),
+		Content:    strings.Repeat("ç", 1000),
+		Links:      links,
+		OccurredAt: time.Now(),
+	}
+}
+
+func TestDiscordRenderStaysUnderCharLimit(t *testing.T) {
+	formatter := discordFormatter{}
+	notices := make([]webhookNotice, formatter.maxBatch())
+	for i := range notices {
+		notices[i] = worstCaseNotice()
+	}
+
+	sent := 0
+	for sent < len(notices) {
+		body, n, err := formatter.render(notices[sent:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			t.Fatal("render made no progress")
+		}
+
+		var post struct {
+			Embeds []discordEmbed `json:"embeds"`
+		}
+		if err := json.Unmarshal(body, &post); err != nil {
+			t.Fatal(err)
+		}
+		if len(post.Embeds) != n {
+			t.Fatalf("body has %d embeds, render reported %d", len(post.Embeds), n)
+		}
+		total := 0
+		for _, embed := range post.Embeds {
+			total += embed.chars()
+		}
+		if total > discordEmbedCharLimit {
+			t.Fatalf("post holds %d characters, limit is %d", total, discordEmbedCharLimit)
+		}
+		sent += n
+	}
+	if sent != len(notices) {
+		t.Fatalf("sent %d notices, want %d", sent, len(notices))
+	}
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"kick-chat/domain"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	CreateWebhookEndpoint(ctx context.Context, endpoint domain.WebhookEndpoint) (*domain.WebhookEndpoint, error)
+	CountWebhookEndpoints(ctx context.Context, userID uuid.UUID) (int, error)
+	GetUserWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]domain.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, userID, endpointID uuid.UUID) error
+	OwnsWebhookEndpoint(ctx context.Context, userID, endpointID uuid.UUID) (bool, error)
+	GetWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+	RequeueWebhookDelivery(ctx context.Context, endpointID, deliveryID uuid.UUID) error
+}
+
+type WebhookEndpointInput struct {
+	URL             string
+	Format          string
+	Events          []string
+	Streamer        string
+	RateLimitPerMin *int
+}
+
+type WebhookDeliveryQuery struct {
+	Status string
+	Cursor string
+	Limit  int
+}
+
+type WebhookDeliveryPage struct {
+	Deliveries []domain.WebhookDelivery `json:"deliveries"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	HasMore    bool                     `json:"has_more"`
+}
+
+type WebhookUseCase interface {
+	CreateEndpoint(ctx context.Context, userID string, input WebhookEndpointInput) (*domain.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context, userID string) ([]domain.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, userID, endpointID string) error
+	ListDeliveries(ctx context.Context, userID, endpointID string, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error)
+	Redeliver(ctx context.Context, userID, endpointID, deliveryID string) error
+}
+
+type webhookUseCase struct {
+	repo       WebhookRepository
+	dispatcher *WebhookDispatcher
+	config     *Config
+}
+
+func NewWebhookUseCase(repo WebhookRepository, dispatcher *WebhookDispatcher) WebhookUseCase {
+	return &webhookUseCase{
+		repo:       repo,
+		dispatcher: dispatcher,
+		config:     AppConfig,
+	}
+}
+
+// validWebhookEvent accepts the listener's event types plus the derived link and alert events
+func validWebhookEvent(name string) bool {
+	if name == webhookEventLink || name == webhookEventAlert {
+		return true
+	}
+	_, ok := eventTypeFactories[EventType(name)]
+	return ok
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// CreateEndpoint returns the endpoint with its secret, the only time the secret is shown
+func (u *webhookUseCase) CreateEndpoint(ctx context.Context, userID string, input WebhookEndpointInput) (*domain.WebhookEndpoint, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+
+	endpoint := domain.WebhookEndpoint{
+		UserID:          currentUserID,
+		URL:             strings.TrimSpace(input.URL),
+		Format:          strings.ToLower(strings.TrimSpace(input.Format)),
+		RateLimitPerMin: u.config.WebhookDefaultRateLimit,
+		Enabled:         true,
+	}
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("%w: url http veya https olmalı", domain.ErrInvalidInput)
+	}
+	if err := checkWebhookHost(ctx, parsed.Hostname()); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	if endpoint.Format == "" {
+		endpoint.Format = domain.WebhookFormatJSON
+	}
+	if !validWebhookFormat(endpoint.Format) {
+		return nil, fmt.Errorf("%w: format json, discord veya slack olmalı", domain.ErrInvalidInput)
+	}
+	if streamer := strings.ToLower(strings.TrimSpace(input.Streamer)); streamer != "" {
+		endpoint.Streamer = &streamer
+	}
+	if input.RateLimitPerMin != nil {
+		endpoint.RateLimitPerMin = *input.RateLimitPerMin
+	}
+	if endpoint.RateLimitPerMin < 1 || endpoint.RateLimitPerMin > u.config.WebhookMaxRateLimit {
+		return nil, fmt.Errorf("%w: dakikalık limit 1 ile %d arasında olmalı", domain.ErrInvalidInput, u.config.WebhookMaxRateLimit)
+	}
+
+	events := input.Events
+	if len(events) == 0 {
+		events = DefaultWebhookEvents
+	}
+	for _, name := range events {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !validWebhookEvent(name) {
+			return nil, fmt.Errorf("%w: bilinmeyen olay tipi: %s", domain.ErrInvalidInput, name)
+		}
+		endpoint.Events = append(endpoint.Events, name)
+	}
+
+	count, err := u.repo.CountWebhookEndpoints(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= u.config.WebhookMaxEndpointsPerUser {
+		return nil, fmt.Errorf("%w: en fazla %d webhook tanımlanabilir", domain.ErrQuotaExceeded, u.config.WebhookMaxEndpointsPerUser)
+	}
+
+	if endpoint.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	created, err := u.repo.CreateWebhookEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.reload(ctx)
+	return created, nil
+}
+
+func (u *webhookUseCase) ListEndpoints(ctx context.Context, userID string) ([]domain.WebhookEndpoint, error) {
+	currentUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrNotFoundAuthorization
+	}
+	endpoints, err := u.repo.GetUserWebhookEndpoints(ctx, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints == nil {
+		endpoints = []domain.WebhookEndpoint{}
+	}
+	return endpoints, nil
+}
+
+func (u *webhookUseCase) DeleteEndpoint(ctx context.Context, userID, endpointID string) error {
+	currentUserID, id, err := parseWebhookIDs(userID, endpointID)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.DeleteWebhookEndpoint(ctx, currentUserID, id); err != nil {
+		return err
+	}
+	u.reload(ctx)
+	return nil
+}
+
+func (u *webhookUseCase) ListDeliveries(ctx context.Context, userID, endpointID string, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error) {
+	id, err := u.ownedEndpoint(ctx, userID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.WebhookDeliveryFilter{
+		EndpointID: id,
+		Status:     strings.ToLower(strings.TrimSpace(query.Status)),
+		Limit:      query.Limit,
+	}
+	switch filter.Status {
+	case "", domain.WebhookStatusPending, domain.WebhookStatusDelivered, domain.WebhookStatusDead:
+	default:
+		return nil, fmt.Errorf("%w: geçersiz durum: %s", domain.ErrInvalidInput, filter.Status)
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeMessageCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Before = &cursor
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = u.config.WebhookDefaultLimit
+	}
+	if filter.Limit > u.config.WebhookMaxLimit {
+		filter.Limit = u.config.WebhookMaxLimit
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+	deliveries, err := u.repo.GetWebhookDeliveries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &WebhookDeliveryPage{Deliveries: deliveries}
+	if len(deliveries) > limit {
+		page.Deliveries = deliveries[:limit]
+		page.HasMore = true
+		last := page.Deliveries[limit-1]
+		page.NextCursor = encodeMessageCursor(domain.MessageCursor{Timestamp: last.CreatedAt, ID: last.ID})
+	}
+	if page.Deliveries == nil {
+		page.Deliveries = []domain.WebhookDelivery{}
+	}
+	return page, nil
+}
+
+// Redeliver moves a dead-lettered delivery back into the queue
+func (u *webhookUseCase) Redeliver(ctx context.Context, userID, endpointID, deliveryID string) error {
+	id, err := u.ownedEndpoint(ctx, userID, endpointID)
+	if err != nil {
+		return err
+	}
+	delivery, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return fmt.Errorf("%w: geçersiz teslimat id", domain.ErrInvalidInput)
+	}
+	if err := u.repo.RequeueWebhookDelivery(ctx, id, delivery); err != nil {
+		return err
+	}
+	u.dispatcher.nudge()
+	return nil
+}
+
+func (u *webhookUseCase) ownedEndpoint(ctx context.Context, userID, endpointID string) (uuid.UUID, error) {
+	currentUserID, id, err := parseWebhookIDs(userID, endpointID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	owned, err := u.repo.OwnsWebhookEndpoint(ctx, currentUserID, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !owned {
+		return uuid.Nil, domain.ErrForbidden
+	}
+	return id, nil
+}
+
+func parseWebhookIDs(userID, endpointID string) (uuid.UUID, uuid.UUID, error) {
+	user, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrNotFoundAuthorization
+	}
+	endpoint, err := uuid.Parse(endpointID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%w: geçersiz webhook id", domain.ErrInvalidInput)
+	}
+	return user, endpoint, nil
+}
+
+// reload applies a change on this replica right away, others pick it up on refresh
+func (u *webhookUseCase) reload(ctx context.Context) {
+	if err := u.dispatcher.Reload(ctx); err != nil {
+		log.Printf("Webhooklar yenilenemedi: %v", err)
+	}
+}
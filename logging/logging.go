@@ -11,7 +11,8 @@ import (
 var logger *zap.Logger
 
 func init() {
-	fmt.Println("log running")
+	// stderr, so "kick-chat export" can write the export itself to stdout
+	fmt.Fprintln(os.Stderr, "log running")
 	encoderCfg := zap.NewProductionEncoderConfig()
 	encoderCfg.TimeKey = "timestamp"
 	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -103,6 +103,16 @@ func GetColorFromHex(hexCode string) aurora.Color {
 	return rgbToAnsi256(r, g, b)
 }
 
+// GetCSSColorFromHex, GetColorFromHex ile aynı doğrulamayı yapar ama rengi HTML için
+// #rrggbb olarak döndürür; geçersiz renklerde fallback kullanılır.
+func GetCSSColorFromHex(hexCode, fallback string) string {
+	r, g, b, err := hexToRGB(hexCode)
+	if err != nil {
+		return fallback
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
 func Timer(name string) func() {
 	start := time.Now()
 	return func() {